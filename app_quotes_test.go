@@ -325,3 +325,67 @@ func Test_QuotesApp(t *testing.T) {
 		})
 	}
 }
+
+func Test_QuotesApp_RunString(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmdline     string
+		wantErr     error
+		wantOptions *appArgs
+	}{
+		{
+			name:    "quoted arguments",
+			cmdline: `quotes get -c "my config.yaml" --mode 'A' -i 'isin 1' -i "isin\"2" -n`,
+			wantOptions: &appArgs{
+				config:     "my config.yaml",
+				configType: defaultConfigType,
+				dryrun:     true,
+				isins:      []string{"isin 1", `isin"2`},
+				workers:    defaultWorkers,
+				mode:       "A",
+			},
+		},
+		{
+			name:    "escaped spaces and comment",
+			cmdline: "/usr/bin/quotes g -d my\\ quotes.db # default workers",
+			wantOptions: &appArgs{
+				configType: defaultConfigType,
+				database:   "my quotes.db",
+				workers:    defaultWorkers,
+				mode:       defaultMode,
+			},
+		},
+		{
+			name:    "help",
+			cmdline: "quotes get -h",
+			wantErr: flag.ErrHelp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			err := flagx.RunString(initApp(), tt.cmdline)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error: got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: got %q, want nil", err)
+			}
+			if !reflect.DeepEqual(argsQuotes, tt.wantOptions) {
+				t.Errorf("options: got %v, want %v", argsQuotes, tt.wantOptions)
+			}
+		})
+	}
+
+	var se *flagx.SyntaxError
+	for _, cmdline := range []string{"quotes get 'isin", "  # only a comment"} {
+		if err := flagx.RunString(initApp(), cmdline); !errors.As(err, &se) {
+			t.Errorf("%q: error: got %v, want *SyntaxError", cmdline, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package flagx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// batch flags of the root command
+const (
+	batchFlagName     = "batch"
+	batchUsage        = "execute the commands of the script `path` (\"-\" for stdin)"
+	keepGoingFlagName = "keep-going"
+	keepGoingUsage    = "with --batch, execute all the commands, instead of stopping at the first failure"
+)
+
+// defaultBatchSource is the default name of a batch script.
+const defaultBatchSource = "batch"
+
+// BatchOptions are the options of RunBatch.
+type BatchOptions struct {
+	Source          string              // name of the script, used in the errors; default "batch"
+	ContinueOnError bool                // execute all the commands, instead of stopping at the first failure
+	Getenv          func(string) string // value of the variables; default os.Getenv
+}
+
+// BatchFailure is a command of a batch script that failed.
+type BatchFailure struct {
+	Line    int    // line of the command in the script, starting from 1
+	Command string // text of the command
+	Err     error  // error of the command
+}
+
+// BatchError is the error returned by RunBatch if some commands failed.
+type BatchError struct {
+	Source   string          // name of the script
+	Commands int             // number of commands executed
+	Failures []*BatchFailure // commands that failed
+}
+
+// Error returns the summary of the failures, with one line per failure.
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d of %d commands failed", e.Source, len(e.Failures), e.Commands)
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "\n%s:%d: %v", e.Source, f.Line, f.Err)
+	}
+	return b.String()
+}
+
+// RunBatch executes the commands of the script read from r, one command per
+// line, dispatching each one to the root command app with name appname.
+//
+// Each line is split into arguments with shell-like rules, as the response
+// files (see ExpandResponseFiles): '#' comments, quotes and backslash escapes.
+// A line ending with an unquoted backslash continues on the next line.
+// The variables "$NAME" and "${NAME}", unquoted or enclosed in double quotes,
+// are replaced by their values (see BatchOptions.Getenv).
+//
+// RunBatch stops at the first failed command, unless opts.ContinueOnError
+// is true. If some commands failed, it returns a *BatchError with
+// the failures and their line numbers. opts can be nil.
+func RunBatch(r io.Reader, appname string, app *Command, opts *BatchOptions) error {
+	return app.runBatch(context.Background(), r, appname, opts, false)
+}
+
+// runBatch is RunBatch, in the context ctx, handling SIGINT if signals is true.
+func (app *Command) runBatch(ctx context.Context, r io.Reader, appname string, opts *BatchOptions, signals bool) error {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	be := &BatchError{Source: opts.Source}
+	if be.Source == "" {
+		be.Source = defaultBatchSource
+	}
+	getenv := opts.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	sc := bufio.NewScanner(r)
+	for line := 1; ; {
+		text, n, ok := readContinuedLine(sc)
+		if !ok {
+			break
+		}
+		start := line
+		line += n
+
+		args, err := SplitExpand(text, getenv)
+		if err == nil && len(args) == 0 {
+			continue
+		}
+		be.Commands++
+		if err == nil {
+			err = app.execArgs(ctx, appname, args, signals)
+		}
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			be.Failures = append(be.Failures, &BatchFailure{Line: start, Command: strings.TrimSpace(text), Err: err})
+			if !opts.ContinueOnError {
+				break
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return wrapNameError(err, be.Source)
+	}
+	if len(be.Failures) == 0 {
+		return nil
+	}
+	return be
+}
+
+// batchArgs parses the batch flags of the root command with name appname:
+// "--batch <path>" and "--keep-going". It returns ok false if the arguments
+// do not begin with the batch flags. After the batch flag, the arguments
+// must contain only the batch flags.
+func batchArgs(appname string, arguments []string) (path string, keepGoing, ok bool, err error) {
+	for j := 0; j < len(arguments); j++ {
+		arg := arguments[j]
+		var name string
+		if strings.HasPrefix(arg, "-") && arg != "-" && arg != "--" {
+			name = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		}
+		switch {
+		case name == keepGoingFlagName:
+			keepGoing = true
+		case name == batchFlagName:
+			if j+1 == len(arguments) {
+				return "", false, false, &Error{Path: appname, Token: arg, Kind: ErrFlagParse,
+					msg: fmt.Sprintf("%s: flag needs an argument: %s", appname, arg)}
+			}
+			j++
+			path, ok = arguments[j], true
+		case strings.HasPrefix(name, batchFlagName+"="):
+			path, ok = name[len(batchFlagName)+1:], true
+		case ok:
+			return "", false, false, &Error{Path: appname, Token: arg, Kind: ErrFlagParse,
+				msg: fmt.Sprintf("%s: unexpected argument with --%s: %s", appname, batchFlagName, arg)}
+		default:
+			return "", false, false, nil
+		}
+	}
+	return path, keepGoing, ok, nil
+}
+
+// runBatchFile executes the commands of the script file at path ("-" for Stdin).
+func (app *Command) runBatchFile(ctx context.Context, appname, path string, keepGoing, signals bool) error {
+	opts := &BatchOptions{Source: path, ContinueOnError: keepGoing}
+	if path == "-" {
+		opts.Source = "stdin"
+		return app.runBatch(ctx, Stdin, appname, opts, signals)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return wrapNameError(err, appname)
+	}
+	defer f.Close()
+	return app.runBatch(ctx, f, appname, opts, signals)
+}
@@ -0,0 +1,201 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testBatchApp returns a command tree that records the executed commands.
+func testBatchApp(calls *[][]string) *Command {
+	exec := func(name string, arguments []string) error {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		fs.SetOutput(new(strings.Builder))
+		fs.Bool("n", false, "dry run")
+		if err := Parse(fs, arguments); err != nil {
+			return err
+		}
+		*calls = append(*calls, append([]string{name}, fs.Args()...))
+		return nil
+	}
+	return &Command{
+		Batch: true,
+		SubCmd: map[string]*Command{
+			"get":     {ParseExec: exec},
+			"sources": {ParseExec: exec},
+		},
+	}
+}
+
+const testBatchScript = `# daily quotes
+get -n $ISIN "${ISIN}-2" '$ISIN'
+
+sources
+gte
+get \
+  -x
+get isin3 # comment
+get 'unterminated
+get isin4
+`
+
+func TestRunBatch(t *testing.T) {
+	getenv := func(name string) string {
+		if name == "ISIN" {
+			return "IT0001"
+		}
+		return ""
+	}
+
+	t.Run("stop on error", func(t *testing.T) {
+		var calls [][]string
+		err := RunBatch(strings.NewReader(testBatchScript), "app", testBatchApp(&calls), &BatchOptions{Source: "daily.txt", Getenv: getenv})
+
+		wantCalls := [][]string{
+			{"app get", "IT0001", "IT0001-2", "$ISIN"},
+			{"app sources"},
+		}
+		if !reflect.DeepEqual(calls, wantCalls) {
+			t.Errorf("calls: got %q, want %q", calls, wantCalls)
+		}
+		var be *BatchError
+		if !errors.As(err, &be) {
+			t.Fatalf("want *BatchError, got %T: %v", err, err)
+		}
+		want := `daily.txt: 1 of 3 commands failed
+daily.txt:5: app: command not found "gte" (did you mean "get"?)`
+		if err.Error() != want {
+			t.Errorf("error: got\n%s\nwant\n%s", err, want)
+		}
+		if be.Failures[0].Command != "gte" || !errors.Is(be.Failures[0].Err, ErrCommandNotFound) {
+			t.Errorf("failure: got %+v", be.Failures[0])
+		}
+		if ExitCode(err) != ExitFailure {
+			t.Errorf("ExitCode = %d, want %d", ExitCode(err), ExitFailure)
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		var calls [][]string
+		err := RunBatch(strings.NewReader(testBatchScript), "app", testBatchApp(&calls), &BatchOptions{ContinueOnError: true, Getenv: getenv})
+
+		wantCalls := [][]string{
+			{"app get", "IT0001", "IT0001-2", "$ISIN"},
+			{"app sources"},
+			{"app get", "isin3"},
+			{"app get", "isin4"},
+		}
+		if !reflect.DeepEqual(calls, wantCalls) {
+			t.Errorf("calls: got %q, want %q", calls, wantCalls)
+		}
+		want := `batch: 3 of 7 commands failed
+batch:5: app: command not found "gte" (did you mean "get"?)
+batch:6: app get: flag provided but not defined: -x
+batch:9: 1:5: unterminated single quote`
+		if err == nil || err.Error() != want {
+			t.Errorf("error: got\n%v\nwant\n%s", err, want)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var calls [][]string
+		if err := RunBatch(strings.NewReader("get a\nget -h\n"), "app", testBatchApp(&calls), nil); err != nil {
+			t.Errorf("error: got %v, want nil", err)
+		}
+		if len(calls) != 1 {
+			t.Errorf("calls: got %q", calls)
+		}
+	})
+}
+
+func TestCommand_Batch(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"script.txt": "get a\nunknown\nget b\n",
+	})
+	script := filepath.Join(dir, "script.txt")
+
+	tests := []struct {
+		name      string
+		args      []string
+		stdin     string
+		wantCalls [][]string
+		wantErr   string
+	}{
+		{
+			name:      "file",
+			args:      []string{"--batch", script},
+			wantCalls: [][]string{{"app get", "a"}},
+			wantErr:   script + ": 1 of 2 commands failed\n" + script + `:2: app: command not found "unknown"`,
+		},
+		{
+			name:      "keep going",
+			args:      []string{"--keep-going", "--batch=" + script},
+			wantCalls: [][]string{{"app get", "a"}, {"app get", "b"}},
+			wantErr:   script + ": 1 of 3 commands failed\n" + script + `:2: app: command not found "unknown"`,
+		},
+		{
+			name:      "stdin",
+			args:      []string{"-batch", "-"},
+			stdin:     "sources\n",
+			wantCalls: [][]string{{"app sources"}},
+		},
+		{
+			name:    "missing file",
+			args:    []string{"--batch", filepath.Join(dir, "missing.txt")},
+			wantErr: "app: open " + filepath.Join(dir, "missing.txt"),
+		},
+		{
+			name:    "missing path",
+			args:    []string{"--batch"},
+			wantErr: "app: flag needs an argument: --batch",
+		},
+		{
+			name:    "extra argument",
+			args:    []string{"--batch", script, "get"},
+			wantErr: "app: unexpected argument with --batch: get",
+		},
+		{
+			name:      "not batch",
+			args:      []string{"get", "--batch", "x"},
+			wantCalls: nil,
+			wantErr:   "app get: flag provided but not defined: -batch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdin := Stdin
+			defer func() { Stdin = oldStdin }()
+			Stdin = strings.NewReader(tt.stdin)
+
+			var calls [][]string
+			err := testBatchApp(&calls).run("app", tt.args)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("error: got %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Errorf("error: got %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls: got %q, want %q", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPrintHelp_Batch(t *testing.T) {
+	var buf strings.Builder
+	if err := PrintHelp(&buf, "app", &Command{Batch: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := `Options:
+    --batch      path  execute the commands of the script path ("-" for stdin)
+    --keep-going       with --batch, execute all the commands, instead of
+                       stopping at the first failure
+`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("PrintHelp: got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ColorMode specifies when the output generated by flagx is styled
+// with ANSI escape sequences.
+type ColorMode int
+
+// ColorMode values.
+const (
+	ColorAuto   ColorMode = iota // style if the output is a terminal and NO_COLOR is not set
+	ColorAlways                  // always style
+	ColorNever                   // never style: plain text
+)
+
+// Color is the global option that controls the styling of the help
+// and of the errors printed by flagx. With the default ColorAuto mode,
+// the output written to a writer that is not a terminal
+// (for example a strings.Builder in a test) is always plain text.
+var Color = ColorAuto
+
+// ANSI escape sequences of the styles.
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// useColor checks if the output written to w must be styled.
+func useColor(w io.Writer) bool {
+	switch Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	_, ok = terminalWidth(f)
+	return ok
+}
+
+// styler applies the ANSI styles, if enabled.
+type styler bool
+
+// style returns s with the given ANSI style, if enabled and s is not empty.
+func (st styler) style(code, s string) string {
+	if !st || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func (st styler) heading(s string) string  { return st.style(ansiBold, s) }
+func (st styler) flagName(s string) string { return st.style(ansiCyan, s) }
+func (st styler) dim(s string) string      { return st.style(ansiDim, s) }
+func (st styler) err(s string) string      { return st.style(ansiRed, s) }
+
+// PrintError prints the error err to w, prefixed by "error: ".
+// The message is styled in red if the colors are enabled for w (see Color).
+// It does nothing if err is nil.
+func PrintError(w io.Writer, err error) {
+	if err == nil {
+		return
+	}
+	st := styler(useColor(w))
+	fmt.Fprintf(w, "%s %s\n", st.style(ansiBold+ansiRed, "error:"), st.err(err.Error()))
+}
@@ -0,0 +1,90 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+func withColor(t *testing.T, mode ColorMode) {
+	t.Helper()
+	old := Color
+	Color = mode
+	t.Cleanup(func() { Color = old })
+}
+
+func Test_useColor(t *testing.T) {
+	var buf strings.Builder
+
+	withColor(t, ColorAlways)
+	if !useColor(&buf) {
+		t.Errorf("ColorAlways: want color")
+	}
+
+	withColor(t, ColorNever)
+	if useColor(os.Stdout) {
+		t.Errorf("ColorNever: want plain text")
+	}
+
+	withColor(t, ColorAuto)
+	if useColor(&buf) {
+		t.Errorf("ColorAuto: want plain text for a non terminal writer")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if useColor(os.Stdout) {
+		t.Errorf("ColorAuto: want plain text with NO_COLOR")
+	}
+}
+
+func TestPrintHelp_color(t *testing.T) {
+	withColor(t, ColorAlways)
+	cmd := &Command{
+		SubCmd: map[string]*Command{
+			"get": {Short: "Get the quotes"},
+		},
+		Flags: func(fs *flag.FlagSet) {
+			AliasedIntVar(fs, new(int), "workers,w", 1, "number of workers")
+		},
+	}
+	var buf strings.Builder
+	if err := PrintHelp(&buf, "app", cmd); err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1b[1mUsage:\x1b[0m\n" +
+		"    app <command> [options]\n" +
+		"\n" +
+		"\x1b[1mAvailable commands:\x1b[0m\n" +
+		"    \x1b[36mget\x1b[0m  Get the quotes\n" +
+		"\n" +
+		"\x1b[1mOptions:\x1b[0m\n" +
+		"    \x1b[36m-w, --workers\x1b[0m int  number of workers\x1b[2m (default 1)\x1b[0m\n"
+	if got := buf.String(); got != want {
+		t.Errorf("PrintHelp:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	err := errors.New("app: command not found")
+
+	withColor(t, ColorNever)
+	var buf strings.Builder
+	PrintError(&buf, err)
+	if got, want := buf.String(), "error: app: command not found\n"; got != want {
+		t.Errorf("plain: got %q, want %q", got, want)
+	}
+
+	withColor(t, ColorAlways)
+	buf.Reset()
+	PrintError(&buf, err)
+	if got, want := buf.String(), "\x1b[1m\x1b[31merror:\x1b[0m \x1b[31mapp: command not found\x1b[0m\n"; got != want {
+		t.Errorf("color: got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	PrintError(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("nil error: got %q", buf.String())
+	}
+}
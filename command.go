@@ -1,10 +1,13 @@
 package flagx
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"os"
 	"path"
 	"strings"
+	"time"
 )
 
 // flagx defined inner errors
@@ -12,6 +15,8 @@ var (
 	ErrInvalidCommandName = errors.New("invalid command name")
 	ErrNoExecFunc         = errors.New("exec function undefined")
 	ErrCommandNotFound    = errors.New("command not found")
+	ErrTopicNotFound      = errors.New("help topic not found")
+	ErrFlagParse          = errors.New("invalid flags")
 )
 
 // ParseExecFunc is the signature of the function that is called
@@ -21,12 +26,137 @@ var (
 // arguments:   the arguments of the command
 type ParseExecFunc func(fullname string, arguments []string) error
 
+// FlagsFunc is the signature of the function that defines
+// the flags of a Command in the flag set fs.
+type FlagsFunc func(fs *flag.FlagSet)
+
 // Command represents a node of the commands tree.
 // Each node has the function to be called if the command is executed
 // and the children sub-commands.
 type Command struct {
 	SubCmd    map[string]*Command // sub-commands of the command
 	ParseExec ParseExecFunc       // function to be executed by the command
+
+	// ParseExecContext, if not nil, is the function to be executed by the command,
+	// in place of ParseExec, with a context that is cancelled when the Timeout
+	// expires or, if the command is executed by Run, when SIGINT is received.
+	ParseExecContext ParseExecContextFunc
+
+	// Timeout, if positive, is the default maximum duration of the execution
+	// of a command defined by ParseExecContext. It can be overridden by the
+	// "--timeout <duration>" flag, that flagx handles and removes from the
+	// arguments passed to ParseExecContext. The flag is looked for among the
+	// flags preceding the first non-flag argument or "--"; the flags defined
+	// by Flags tell the flags followed by a value. A "timeout" flag defined
+	// by Flags is left to the command.
+	//
+	// Timeout requires ParseExecContext: a command defined by ParseExec,
+	// that can't be cancelled, with a positive Timeout is not executed
+	// and returns an error of kind ErrTimeoutNoContext.
+	Timeout time.Duration
+
+	Short    string   // one-line description, shown in the list of commands of the parent
+	Long     string   // long description, shown in the help of the command
+	Examples []string // usage examples, shown in the help of the command
+	Group    string   // heading under which the command is listed in the help of the parent
+
+	// Positionals describe the positional arguments of the command,
+	// shown in the usage line and in the help of the command.
+	Positionals []*Positional
+
+	// HelpTemplate, if not empty, is the text/template of the help of the command,
+	// used in place of DefaultHelpTemplate. See HelpData for the data model.
+	HelpTemplate string
+
+	// Flags, if not nil, defines the flags of the command.
+	// It is used by the generated help to list the options of the command,
+	// and it can be called by ParseExec to define the flags before parsing.
+	// If Flags is defined, a help argument ("-h", "-help" or "--help") passed
+	// as first argument shows the generated help, as the help command, without
+	// executing the command; see HelpUsage for the help arguments that reach
+	// the flag set of the command.
+	Flags FlagsFunc
+
+	// HelpCommand, if true, adds the "help" sub-command, unless a sub-command
+	// with the same name is already defined. "help [command...]" prints the help
+	// of the command found following the path of sub-command names (or aliases)
+	// from this command; "help <topic>" prints the help topic.
+	HelpCommand bool
+
+	// Topics are the help topics that are not commands, printed by the
+	// "help <topic>" command. The key is the comma separated names of the topic.
+	Topics map[string]*Topic
+
+	// Hidden, if true, excludes the command from the help and the completion.
+	// The command can still be executed.
+	Hidden bool
+
+	// HiddenAliases are aliases of the command, among the names of its key
+	// in the SubCmd map of the parent, excluded from the help and the completion.
+	// The aliases can still be used.
+	HiddenAliases []string
+
+	// Deprecated, if not empty, marks the command as deprecated:
+	// the command still works, but a warning with the Deprecated message
+	// is printed before executing it. The message should name the replacement, if any.
+	// A deprecated command is also hidden.
+	Deprecated string
+
+	// RetiredAliases are aliases of the command, among the names of its key
+	// in the SubCmd map of the parent, that are retired: they still work,
+	// but they are hidden and a warning naming the primary name is printed
+	// each time they are used.
+	RetiredAliases []string
+
+	// ResponseFiles, if true in the root command, expands the "@path"
+	// arguments with the content of the files (see ExpandResponseFiles)
+	// before the arguments are dispatched to the sub-commands.
+	ResponseFiles bool
+
+	// Before, if not nil, is called before ParseExec: if it returns an error,
+	// ParseExec is not called. After, if not nil, is called after ParseExec,
+	// even if ParseExec or the before hooks failed, with their error:
+	// the error returned by After is the error of the command.
+	Before HookFunc
+	After  AfterHookFunc
+
+	// PersistentBefore and PersistentAfter are like Before and After,
+	// but they are called for the command and for all its sub-commands:
+	// the persistent before hooks from the root to the executed command,
+	// before its Before hook, and the persistent after hooks from the
+	// executed command to the root, after its After hook.
+	PersistentBefore HookFunc
+	PersistentAfter  AfterHookFunc
+
+	// Middleware wraps the ParseExec, hooks included, of the command
+	// and of all its sub-commands. The middlewares are composed along the path
+	// from the root to the executed command: the first middleware of the root
+	// is the outermost.
+	Middleware []Middleware
+
+	// UserAliases are shortcuts defined by the user, usually loaded from
+	// a configuration file (see LoadUserAliases): the key is the name of the
+	// alias and the value is its expansion (example: "q" -> "get -n -w 8").
+	// A user alias is expanded when its name is passed in place of the name
+	// of a sub-command of the command. The sub-commands take precedence:
+	// a user alias cannot shadow their names.
+	UserAliases map[string]string
+
+	// Plugins, if true, enables the external plugin sub-commands:
+	// a name that is neither a sub-command nor a user alias is executed
+	// as the "<fullname>-<name>" executable (example: "app-foo", or
+	// "app-get-foo" for the plugins of "app get"), searched in PluginDirs
+	// and then in PATH. The plugin is executed with the remaining arguments
+	// and the flagx Stdin, Stdout and Stderr; a non-zero exit status is
+	// returned as a *PluginExitError. The plugins are listed in the help.
+	Plugins    bool
+	PluginDirs []string
+
+	// Batch, if true in the root command, enables the batch execution:
+	// "--batch <path>" executes the commands of the script file at path
+	// ("-" for Stdin), stopping at the first failed command unless
+	// "--keep-going" is passed too. See RunBatch.
+	Batch bool
 }
 
 // handleSubCmd checks if the command must be executed
@@ -35,45 +165,167 @@ type Command struct {
 // fullname is the join of the ancestors or self command names, starting from root command.
 // example: cmdfullname = "appname cmd1 subcmd11"
 func (cmd *Command) handleSubCmd(fullname string, arguments []string) error {
+	return cmd.dispatch(fullname, &dispatchState{ctx: context.Background()}, arguments)
+}
+
+// dispatchState is the state of the dispatch of the arguments
+// from the root command to the executed command.
+type dispatchState struct {
+	names    []string        // visible names of the command in the parent (nil for the root)
+	parents  []*Command      // ancestors of the command, from the root
+	ctx      context.Context // context of the execution
+	signals  bool            // handle SIGINT during the execution
+	expanded map[string]bool // user aliases already expanded, to detect the loops
+}
+
+// child returns the dispatch state of the sub-command sc of cmd.
+func (ds *dispatchState) child(cmd *Command, sc *subCommand) *dispatchState {
+	parents := make([]*Command, len(ds.parents), len(ds.parents)+1)
+	copy(parents, ds.parents)
+	return &dispatchState{
+		names:    sc.visibleNames(),
+		parents:  append(parents, cmd),
+		ctx:      ds.ctx,
+		signals:  ds.signals,
+		expanded: ds.expanded,
+	}
+}
+
+// dispatch is handleSubCmd, with the state of the dispatch.
+func (cmd *Command) dispatch(fullname string, ds *dispatchState, arguments []string) error {
 
 	var arg0 string
 	if len(arguments) > 0 {
 		arg0 = arguments[0]
 	}
 
-	if arg0 == "" || strings.HasPrefix(arg0, "-") || (len(cmd.SubCmd) == 0) {
+	if arg0 == "" || strings.HasPrefix(arg0, "-") || (len(cmd.SubCmd) == 0 && !cmd.HelpCommand && len(cmd.UserAliases) == 0 && !cmd.Plugins) {
 		// if no argument is passed
 		// or the first argument begin with "-"
 		// or the command has no subcommand
 		// then parse the current command
 
-		if cmd.ParseExec == nil {
-			return wrapNameError(ErrNoExecFunc, fullname)
+		noExec := cmd.ParseExec == nil && cmd.ParseExecContext == nil
+		if isHelpArg(arg0) && (noExec || cmd.Flags != nil) {
+			// the generated help, as the help command
+			return cmd.showHelp(fullname, ds.names)
+		}
+		if noExec {
+			return newError(ErrNoExecFunc, fullname, "")
 		}
 
-		return cmd.ParseExec(fullname, arguments)
+		return helpResult(fullname, flagParseError(fullname, cmd.execute(fullname, ds, arguments)))
 	}
 
 	// arg0 must be the name of a sub command
-	for names, subcmd := range cmd.SubCmd {
-		ns := splitTrimSpace(names, ",")
-		if len(ns) == 0 {
-			return wrapNameErrorString(ErrInvalidCommandName, fullname, names)
+	scs, err := cmd.subCommands(fullname)
+	if err != nil {
+		return err
+	}
+	for _, sc := range scs {
+		if contains(sc.names, arg0) {
+			sc.cmd.warnDeprecatedCommand(fullname, sc.primary(), arg0)
+			// parse the subcommand
+			return sc.cmd.dispatch(fullname+" "+sc.primary(), ds.child(cmd, sc), arguments[1:])
 		}
+	}
 
-		if contains(ns, arg0) {
-			// parse the subcommand
-			return subcmd.handleSubCmd(fullname+" "+ns[0], arguments[1:])
+	if _, ok := cmd.UserAliases[arg0]; ok {
+		return cmd.expandUserAlias(fullname, ds, arg0, arguments[1:])
+	}
+
+	if cmd.Plugins {
+		if path, ok := cmd.lookPlugin(fullname, arg0); ok {
+			return runPlugin(ds, fullname+" "+arg0, path, arguments[1:])
 		}
 	}
 
-	return wrapNameErrorString(ErrCommandNotFound, fullname, arg0)
+	return newError(ErrCommandNotFound, fullname, arg0, suggestCommands(arg0, scs)...)
 }
 
 // Run execute the `app` command with the command-line arguments.
 // The name of the `app` command is obtained from the `os.Args[0]` argument.
+// It is RunContext with the background context.
 func Run(app *Command) error {
+	return RunContext(context.Background(), app)
+}
+
+// RunContext executes the `app` command with the command-line arguments, as Run,
+// passing ctx to the commands defined by ParseExecContext. During the execution
+// of such a command, the first SIGINT cancels its context, and the command
+// returns ErrInterrupted; a second SIGINT terminates the program.
+func RunContext(ctx context.Context, app *Command) error {
 	appname := path.Base(os.Args[0])
 
-	return app.handleSubCmd(appname, os.Args[1:])
+	return app.runContext(ctx, appname, os.Args[1:], true)
+}
+
+// RunString executes the `app` command with the command line cmdline,
+// split into arguments by Split: the first argument is the name of the
+// `app` command, as os.Args[0]. It is useful for tests, for example:
+//
+//	err := flagx.RunString(app, `app get -n 'isin 1' "isin 2"`)
+//
+// A malformed or empty command line returns a *SyntaxError.
+func RunString(app *Command, cmdline string) error {
+	args, err := Split(cmdline)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return &SyntaxError{Line: 1, Col: 1, Msg: "missing command name"}
+	}
+	return app.run(path.Base(args[0]), args[1:])
+}
+
+// exit is the function called by RunMain to terminate the program.
+// It can be redefined for test purposes.
+var exit = os.Exit
+
+// RunMain executes the `app` command with the command-line arguments, as Run,
+// then terminates the program with the exit code of the error (see ExitCode).
+// The error, if any, is printed to Stderr by PrintError; flag.ErrHelp,
+// *PluginExitError and the *Error already printed (see Error.Printed)
+// are not printed.
+func RunMain(app *Command) {
+	err := Run(app)
+	var pe *PluginExitError
+	if err != nil && !errors.Is(err, flag.ErrHelp) && !errors.As(err, &pe) && !isPrinted(err) {
+		PrintError(Stderr, err)
+	}
+	exit(ExitCode(err))
+}
+
+// run executes the root command `app`, with the given name and arguments.
+func (app *Command) run(appname string, arguments []string) error {
+	return app.runContext(context.Background(), appname, arguments, false)
+}
+
+// runContext executes the root command `app`, with the given name and arguments,
+// in the context ctx, handling SIGINT if signals is true.
+func (app *Command) runContext(ctx context.Context, appname string, arguments []string, signals bool) error {
+	if app.Batch {
+		path, keepGoing, ok, err := batchArgs(appname, arguments)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return app.runBatchFile(ctx, appname, path, keepGoing, signals)
+		}
+	}
+	return app.execArgs(ctx, appname, arguments, signals)
+}
+
+// execArgs executes the root command `app`, with the given name and arguments,
+// expanding the response files if enabled.
+func (app *Command) execArgs(ctx context.Context, appname string, arguments []string, signals bool) error {
+	if app.ResponseFiles {
+		var err error
+		arguments, err = ExpandResponseFiles(arguments)
+		if err != nil {
+			return wrapNameError(err, appname)
+		}
+	}
+
+	return app.dispatch(appname, &dispatchState{ctx: ctx, signals: signals}, arguments)
 }
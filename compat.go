@@ -0,0 +1,213 @@
+package flagx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity of a Change between two versions of a command.
+type Severity int
+
+// Severity values, in increasing order.
+const (
+	SeverityInfo     Severity = iota // compatible change, like a new command or flag
+	SeverityWarning                  // change that could affect the users, like a changed default
+	SeverityBreaking                 // change that breaks the scripts using the previous version
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityBreaking:
+		return "breaking"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Change is a change between two versions of a command,
+// as reported by Compare and CompareCommands.
+type Change struct {
+	Severity Severity // severity of the change
+	Path     string   // full name of the changed command (example: "app get")
+	Flag     string   // primary name of the changed flag; empty for a command change
+	Message  string   // description of the change
+}
+
+// String returns the description of the change with its severity and position.
+// For example:
+//
+//	breaking: app get: flag --proxy: removed
+func (c *Change) String() string {
+	if c.Flag != "" {
+		return fmt.Sprintf("%s: %s: flag %s: %s", c.Severity, c.Path, flagArg(c.Flag), c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Severity, c.Path, c.Message)
+}
+
+// BreakingChanges returns the changes with SeverityBreaking.
+func BreakingChanges(changes []*Change) []*Change {
+	res := []*Change{}
+	for _, c := range changes {
+		if c.Severity == SeverityBreaking {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// CompareCommands compares two versions of the root command with name appname,
+// and returns the changes from the old to the current version (see Compare).
+func CompareCommands(appname string, old, current *Command) ([]*Change, error) {
+	oldSchema, err := NewSchema(appname, old)
+	if err != nil {
+		return nil, err
+	}
+	curSchema, err := NewSchema(appname, current)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(oldSchema, curSchema), nil
+}
+
+// Compare compares two versions of the schema of a command, and returns
+// the changes from the old to the current version classified by severity.
+//
+// The commands and flags are matched by name: a command or flag whose previous
+// primary name is now an alias is renamed, not removed.
+// Breaking changes are: removed commands, flags and aliases (a retired alias
+// is not removed), changed flag types, removed negations, new required flags
+// and removed accepted values.
+// Warnings are: deprecated commands and flags, changed defaults
+// and changed environment variables.
+// The other changes, like new commands and flags, are informational.
+func Compare(old, current *Schema) []*Change {
+	cl := &changeList{}
+	cl.compareCommand(old, current)
+	return cl.changes
+}
+
+// changeList collects the changes found by Compare.
+type changeList struct {
+	changes []*Change
+}
+
+// add adds a change of the command at path, or of its flag if not empty.
+func (cl *changeList) add(sev Severity, path, flag, format string, a ...interface{}) {
+	cl.changes = append(cl.changes, &Change{sev, path, flag, fmt.Sprintf(format, a...)})
+}
+
+// compareCommand compares two versions of the same command, recursively.
+func (cl *changeList) compareCommand(old, cur *Schema) {
+	path := cur.Path
+
+	if old.Name != cur.Name {
+		cl.add(SeverityInfo, path, "", "renamed from %q", old.Name)
+	}
+	for _, a := range old.Aliases {
+		if !contains(cur.names(), a) {
+			cl.add(SeverityBreaking, path, "", "alias %q removed", a)
+		}
+	}
+	for _, a := range cur.Aliases {
+		if !contains(old.names(), a) {
+			cl.add(SeverityInfo, path, "", "alias %q added", a)
+		}
+	}
+	if !old.Hidden && cur.Hidden {
+		cl.add(SeverityInfo, path, "", "command hidden")
+	}
+	if old.Deprecated == "" && cur.Deprecated != "" {
+		cl.add(SeverityWarning, path, "", "command deprecated: %s", cur.Deprecated)
+	}
+
+	// the flags and commands are matched by the old primary name,
+	// that could be an alias of the current version
+	matchedFlags := map[*FlagSchema]bool{}
+	for _, of := range old.Flags {
+		if cf := cur.flag(of.Name); cf != nil {
+			matchedFlags[cf] = true
+			cl.compareFlag(path, of, cf)
+		} else {
+			cl.add(SeverityBreaking, path, of.Name, "removed")
+		}
+	}
+	for _, cf := range cur.Flags {
+		if matchedFlags[cf] {
+			continue
+		}
+		if cf.Required {
+			cl.add(SeverityBreaking, path, cf.Name, "required flag added")
+		} else {
+			cl.add(SeverityInfo, path, cf.Name, "added")
+		}
+	}
+
+	matchedCommands := map[*Schema]bool{}
+	for _, oc := range old.Commands {
+		if cc := cur.command(oc.Name); cc != nil {
+			matchedCommands[cc] = true
+			cl.compareCommand(oc, cc)
+		} else {
+			cl.add(SeverityBreaking, oc.Path, "", "command removed")
+		}
+	}
+	for _, cc := range cur.Commands {
+		if !matchedCommands[cc] {
+			cl.add(SeverityInfo, cc.Path, "", "command added")
+		}
+	}
+}
+
+// compareFlag compares two versions of the same flag of the command at path.
+func (cl *changeList) compareFlag(path string, old, cur *FlagSchema) {
+	name := cur.Name
+
+	if old.Name != name {
+		cl.add(SeverityInfo, path, name, "renamed from %s", flagArg(old.Name))
+	}
+	for _, a := range old.Aliases {
+		if !contains(cur.names(), a) {
+			cl.add(SeverityBreaking, path, name, "alias %s removed", flagArg(a))
+		}
+	}
+	for _, a := range cur.Aliases {
+		if !contains(old.names(), a) {
+			cl.add(SeverityInfo, path, name, "alias %s added", flagArg(a))
+		}
+	}
+	if old.Type != cur.Type {
+		cl.add(SeverityBreaking, path, name, "type changed from %s to %s", old.Type, cur.Type)
+	}
+	if old.Negatable && !cur.Negatable {
+		cl.add(SeverityBreaking, path, name, "negation removed")
+	}
+	if !old.Required && cur.Required {
+		cl.add(SeverityBreaking, path, name, "flag is now required")
+	}
+	if len(old.Enum) > 0 {
+		removed := []string{}
+		for _, v := range old.Enum {
+			if len(cur.Enum) > 0 && !contains(cur.Enum, v) {
+				removed = append(removed, v)
+			}
+		}
+		if len(removed) > 0 {
+			cl.add(SeverityBreaking, path, name, "accepted values removed: %s", strings.Join(removed, ", "))
+		}
+	} else if len(cur.Enum) > 0 {
+		cl.add(SeverityBreaking, path, name, "accepted values restricted to: %s", strings.Join(cur.Enum, ", "))
+	}
+	if old.Default != cur.Default {
+		cl.add(SeverityWarning, path, name, "default changed from %q to %q", old.Default, cur.Default)
+	}
+	if old.Env != cur.Env {
+		cl.add(SeverityWarning, path, name, "environment variable changed from %q to %q", old.Env, cur.Env)
+	}
+	if old.Deprecated == "" && cur.Deprecated != "" {
+		cl.add(SeverityWarning, path, name, "flag deprecated: %s", cur.Deprecated)
+	}
+}
@@ -0,0 +1,150 @@
+package flagx
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestCompareCommands(t *testing.T) {
+	var (
+		config  string
+		mode    string
+		workers int
+		dryrun  bool
+		proxy   string
+		isins   []string
+		verbose bool
+		output  string
+	)
+
+	old := &Command{
+		SubCmd: map[string]*Command{
+			"get,g,fetch": {
+				Flags: func(fs *flag.FlagSet) {
+					AliasedStringVar(fs, &config, "config,c,cfg", "", "config file")
+					AliasedStringVar(fs, &mode, "mode,m", "1", "result mode", Enum("1", "U", "A"))
+					AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+					AliasedBoolVar(fs, &dryrun, "dry-run,n", false, "dry run", Negatable())
+					AliasedStringVar(fs, &proxy, "proxy,p", "", "proxy", Env("PROXY"))
+					AliasedStringsVar(fs, &isins, "isins,i", "isins")
+				},
+			},
+			"tor,t":     {},
+			"sources,s": {},
+		},
+	}
+
+	current := &Command{
+		SubCmd: map[string]*Command{
+			"get,g,fetch": {
+				RetiredAliases: []string{"fetch"},
+				Flags: func(fs *flag.FlagSet) {
+					AliasedStringVar(fs, &config, "config,c", "", "config file")
+					AliasedStringVar(fs, &mode, "mode,m", "1", "result mode", Enum("1", "A"))
+					AliasedStringVar(fs, &output, "workers,w", "1", "number of workers")
+					AliasedBoolVar(fs, &dryrun, "dry-run,n", true, "dry run")
+					AliasedStringVar(fs, &proxy, "proxy,p", "", "proxy", Env("HTTP_PROXY"), Deprecated("use --config"))
+					AliasedBoolVar(fs, &verbose, "verbose,v", false, "verbose")
+					AliasedStringVar(fs, &output, "output,o", "", "output file", Required())
+				},
+			},
+			"sources": {Deprecated: "use get --sources"},
+			"import":  {},
+		},
+	}
+
+	changes, err := CompareCommands("app", old, current)
+	if err != nil {
+		t.Fatalf("CompareCommands() error = %v, want nil", err)
+	}
+
+	got := []string{}
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`breaking: app get: flag --config: alias --cfg removed`,
+		`breaking: app get: flag --dry-run: negation removed`,
+		`warning: app get: flag --dry-run: default changed from "false" to "true"`,
+		`breaking: app get: flag --isins: removed`,
+		`breaking: app get: flag --mode: accepted values removed: U`,
+		`warning: app get: flag --proxy: environment variable changed from "PROXY" to "HTTP_PROXY"`,
+		`warning: app get: flag --proxy: flag deprecated: use --config`,
+		`breaking: app get: flag --workers: type changed from int to string`,
+		`breaking: app get: flag --output: required flag added`,
+		`info: app get: flag --verbose: added`,
+		`breaking: app sources: alias "s" removed`,
+		`warning: app sources: command deprecated: use get --sources`,
+		`breaking: app tor: command removed`,
+		`info: app import: command added`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareCommands():\ngot\n%q\nwant\n%q", got, want)
+	}
+
+	if n := len(BreakingChanges(changes)); n != 8 {
+		t.Errorf("BreakingChanges(): got %d changes, want 8", n)
+	}
+
+	changes, err = CompareCommands("app", old, old)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("CompareCommands() of same command: got %v, %v, want no changes", changes, err)
+	}
+}
+
+func TestCompareCommands_rename(t *testing.T) {
+	var proxy string
+
+	old := &Command{
+		SubCmd: map[string]*Command{
+			"tor,t": {
+				Flags: func(fs *flag.FlagSet) {
+					AliasedStringVar(fs, &proxy, "proxy", "", "proxy")
+				},
+			},
+		},
+	}
+	current := &Command{
+		SubCmd: map[string]*Command{
+			"onion,tor,t": {
+				Flags: func(fs *flag.FlagSet) {
+					AliasedStringVar(fs, &proxy, "http-proxy,proxy", "", "proxy")
+				},
+			},
+		},
+	}
+
+	changes, err := CompareCommands("app", old, current)
+	if err != nil {
+		t.Fatalf("CompareCommands() error = %v, want nil", err)
+	}
+	got := []string{}
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`info: app onion: renamed from "tor"`,
+		`info: app onion: flag --http-proxy: renamed from --proxy`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareCommands():\ngot\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	tests := []struct {
+		s    Severity
+		want string
+	}{
+		{SeverityInfo, "info"},
+		{SeverityWarning, "warning"},
+		{SeverityBreaking, "breaking"},
+		{Severity(9), "Severity(9)"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("Severity.String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package flagx
+
+import (
+	"sort"
+	"strings"
+)
+
+// Complete returns the completions of the last argument of args, the
+// arguments of the root command app with name appname (without the name).
+// The completions are sorted and include:
+//
+//   - the visible names of the sub-commands, the user aliases and the plugins,
+//     if the last argument follows the names of the parent commands;
+//   - the visible names of the commands and the help topics,
+//     if the last argument follows the help command;
+//   - the visible flags of the command, as "-n", "--name" and "--no-name",
+//     if the last argument begins with "-".
+//
+// An empty last argument completes the names of the sub-commands.
+func Complete(appname string, app *Command, args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	words, last := args[:len(args)-1], args[len(args)-1]
+
+	cmd, fullname, walking := app, appname, true
+	for j, w := range words {
+		if w == "--" {
+			return nil
+		}
+		if !walking || strings.HasPrefix(w, "-") {
+			walking = false
+			continue
+		}
+		scs, err := cmd.subCommands(fullname)
+		if err != nil {
+			return nil
+		}
+		found := findSubCommand(scs, w)
+		if found == nil {
+			walking = false
+			continue
+		}
+		if found.key == helpCommandName && cmd.HelpCommand && !cmd.hasSubCmdName(helpCommandName) {
+			return completeHelp(cmd, fullname, words[j+1:], last)
+		}
+		cmd, fullname = found.cmd, fullname+" "+found.primary()
+	}
+
+	if strings.HasPrefix(last, "-") {
+		return completeFlags(cmd, fullname, last)
+	}
+	if !walking {
+		return nil
+	}
+	return cmd.completeNames(fullname, last)
+}
+
+// completeNames returns the names of the sub-commands, the user aliases
+// and the plugins of the command, beginning with prefix.
+func (cmd *Command) completeNames(fullname, prefix string) []string {
+	scs, err := cmd.subCommands(fullname)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, sc := range visibleSubCommands(scs) {
+		names = append(names, sc.visibleNames()...)
+	}
+	for _, a := range cmd.activeUserAliases(scs) {
+		names = append(names, a.name)
+	}
+	names = append(names, cmd.plugins(fullname, scs)...)
+	return completions(names, prefix)
+}
+
+// completeHelp returns the completions of the last argument of the help
+// command of cmd, following the path of sub-command names.
+func completeHelp(cmd *Command, fullname string, path []string, last string) []string {
+	names := []string{}
+	if len(path) == 0 {
+		for _, t := range cmd.topics() {
+			names = append(names, t.names...)
+		}
+	}
+	for _, name := range path {
+		scs, err := cmd.subCommands(fullname)
+		if err != nil {
+			return nil
+		}
+		found := findSubCommand(scs, name)
+		if found == nil {
+			return nil
+		}
+		cmd, fullname = found.cmd, fullname+" "+found.primary()
+	}
+
+	scs, err := cmd.subCommands(fullname)
+	if err != nil {
+		return nil
+	}
+	for _, sc := range visibleSubCommands(scs) {
+		names = append(names, sc.visibleNames()...)
+	}
+	return completions(names, last)
+}
+
+// completeFlags returns the visible flags of the command beginning with prefix.
+func completeFlags(cmd *Command, fullname, prefix string) []string {
+	names := []string{}
+	for _, d := range cmd.flagDefs(fullname) {
+		for _, n := range d.shortNames() {
+			names = append(names, "-"+n)
+		}
+		for _, n := range d.longNames() {
+			names = append(names, "--"+n)
+			if d.negatable {
+				names = append(names, "--"+negatedName(n))
+			}
+		}
+	}
+	return completions(names, prefix)
+}
+
+// completions returns the sorted unique names beginning with prefix.
+func completions(names []string, prefix string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, n := range names {
+		if strings.HasPrefix(n, prefix) && !seen[n] {
+			seen[n] = true
+			res = append(res, n)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,64 @@
+package flagx
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func testCompleteApp() *Command {
+	return &Command{
+		HelpCommand: true,
+		Topics: map[string]*Topic{
+			"environment,env": {Short: "Environment variables"},
+		},
+		UserAliases: map[string]string{
+			"q":   "get -n",
+			"get": "sources",
+		},
+		SubCmd: map[string]*Command{
+			"get,g": {
+				Flags: func(fs *flag.FlagSet) {
+					AliasedIntVar(fs, new(int), "workers,w", 1, "number of workers")
+					AliasedBoolVar(fs, new(bool), "dry-run,n", false, "trial run", Negatable())
+					AliasedStringVar(fs, new(string), "proxy", "", "proxy", Hidden())
+				},
+				SubCmd: map[string]*Command{
+					"isins,i": {},
+				},
+			},
+			"sources,src": {},
+			"debug":       {Hidden: true},
+		},
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"empty", nil, []string{"g", "get", "help", "q", "sources", "src"}},
+		{"prefix", []string{"s"}, []string{"sources", "src"}},
+		{"hidden", []string{"deb"}, []string{}},
+		{"sub-command", []string{"g", ""}, []string{"i", "isins"}},
+		{"flags", []string{"get", "-"}, []string{"--dry-run", "--no-dry-run", "--workers", "-n", "-w"}},
+		{"long flags", []string{"get", "--w"}, []string{"--workers"}},
+		{"after flag", []string{"get", "-n", ""}, nil},
+		{"flag after flag", []string{"get", "-n", "--no"}, []string{"--no-dry-run"}},
+		{"after --", []string{"get", "--", "-"}, nil},
+		{"unknown", []string{"unknown", ""}, nil},
+		{"help", []string{"help", ""}, []string{"env", "environment", "g", "get", "help", "sources", "src"}},
+		{"help sub-command", []string{"help", "get", ""}, []string{"i", "isins"}},
+		{"help unknown", []string{"help", "unknown", ""}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Complete("app", testCompleteApp(), tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
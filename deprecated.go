@@ -0,0 +1,87 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+)
+
+// warnDeprecated prints the deprecation warning of `what`,
+// followed by the message msg, to the output of flag.CommandLine.
+func warnDeprecated(what, msg string) {
+	fmt.Fprintf(flag.CommandLine.Output(), "warning: %s is deprecated: %s\n", what, msg)
+}
+
+// useInstead returns the deprecation message of a retired alias.
+func useInstead(name string) string {
+	return fmt.Sprintf("use %q instead", name)
+}
+
+// flagArg returns the flag name as passed in the command line: "-n" or "--name".
+func flagArg(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// Hidden option hides the flag from the help, the documentation and the completion.
+// The flag can still be used.
+func Hidden() FlagOption {
+	return func(fi *flagInfo) {
+		fi.hidden = true
+	}
+}
+
+// HiddenAliases option hides the given aliases of the flag from the help,
+// the documentation and the completion. The aliases can still be used.
+func HiddenAliases(names ...string) FlagOption {
+	return func(fi *flagInfo) {
+		fi.hiddenAliases = append(fi.hiddenAliases, names...)
+	}
+}
+
+// Deprecated option marks the flag as deprecated: the flag still works,
+// but a warning with the message msg is printed each time it is used.
+// The message should name the replacement, if any.
+// A deprecated flag is also hidden.
+func Deprecated(msg string) FlagOption {
+	return func(fi *flagInfo) {
+		fi.deprecated = msg
+		fi.hidden = true
+	}
+}
+
+// RetiredAliases option retires the given aliases of the flag, keeping
+// the primary name: the aliases still work, but they are hidden and
+// a warning naming the primary name is printed each time they are used.
+func RetiredAliases(names ...string) FlagOption {
+	return func(fi *flagInfo) {
+		fi.retiredAliases = append(fi.retiredAliases, names...)
+	}
+}
+
+// isHiddenName checks if the name of the flag is hidden.
+func (fi *flagInfo) isHiddenName(name string) bool {
+	return fi.hidden || contains(fi.hiddenAliases, name) || contains(fi.retiredAliases, name)
+}
+
+// warnDeprecatedFlag prints the deprecation warning, if any, of the flag name.
+func (fi *flagInfo) warnDeprecatedFlag(name string) {
+	if fi.deprecated != "" {
+		warnDeprecated("flag "+flagArg(name), fi.deprecated)
+	} else if contains(fi.retiredAliases, name) {
+		warnDeprecated("flag "+flagArg(name), useInstead(flagArg(fi.primary())))
+	}
+}
+
+// warnDeprecatedCommand prints the deprecation warning, if any,
+// of the sub-command cmd called with the given name.
+// parent is the full name of the parent command,
+// and primary is the primary name of the sub-command.
+func (cmd *Command) warnDeprecatedCommand(parent, primary, name string) {
+	if cmd.Deprecated != "" {
+		warnDeprecated(fmt.Sprintf("command %q", parent+" "+name), cmd.Deprecated)
+	} else if contains(cmd.RetiredAliases, name) {
+		warnDeprecated(fmt.Sprintf("command %q", parent+" "+name), useInstead(parent+" "+primary))
+	}
+}
@@ -0,0 +1,147 @@
+package flagx
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_DeprecatedFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        string
+		wantWarning string
+	}{
+		{
+			name: "primary name",
+			args: "--proxy url",
+		},
+		{
+			name: "alias",
+			args: "-p url",
+		},
+		{
+			name:        "retired alias",
+			args:        "--http-proxy url",
+			wantWarning: `warning: flag --http-proxy is deprecated: use "--proxy" instead`,
+		},
+		{
+			name:        "deprecated flag",
+			args:        "--old-workers 2",
+			wantWarning: `warning: flag --old-workers is deprecated: use "--workers"`,
+		},
+		{
+			name: "hidden flag",
+			args: "--debug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				proxy   string
+				workers int
+				debug   bool
+			)
+
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&out)
+			AliasedStringVar(fs, &proxy, "proxy,p,http-proxy", "", "default proxy", RetiredAliases("http-proxy"))
+			AliasedIntVar(fs, &workers, "old-workers", 1, "number of workers", Deprecated(`use "--workers"`))
+			AliasedBoolVar(fs, &debug, "debug", false, "debug mode", Hidden())
+
+			if err := fs.Parse(splitTrimSpace(tt.args, " ")); err != nil {
+				t.Fatalf("error: got %q, want nil", err)
+			}
+			if got := out.String(); !strings.Contains(got, tt.wantWarning) || (tt.wantWarning == "" && got != "") {
+				t.Errorf("warning: got %q, want %q", got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func Test_PrintDefaults_Hidden(t *testing.T) {
+	var (
+		proxy   string
+		workers int
+		debug   bool
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	AliasedStringVar(fs, &proxy, "proxy,p,http-proxy,x", "", "default proxy", RetiredAliases("http-proxy"), HiddenAliases("x"))
+	AliasedIntVar(fs, &workers, "workers,w,old-workers", 1, "number of workers", RetiredAliases("old-workers"))
+	AliasedBoolVar(fs, &debug, "debug", false, "debug mode", Hidden())
+
+	var buf strings.Builder
+	fs.SetOutput(&buf)
+	PrintDefaults(fs)
+
+	want := `    -p, --proxy   string  default proxy
+    -w, --workers int     number of workers (default 1)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("PrintDefaults(): got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCommand_Deprecated(t *testing.T) {
+	exec := func(name string, arguments []string) error { return nil }
+
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get,g": {
+				ParseExec: exec,
+			},
+			"tor,t": {
+				ParseExec:  exec,
+				Deprecated: `use "app get --check-tor" instead`,
+			},
+			"sources,s,src": {
+				ParseExec:      exec,
+				RetiredAliases: []string{"src"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        string
+		wantWarning string
+	}{
+		{
+			name: "command",
+			args: "get",
+		},
+		{
+			name:        "deprecated command",
+			args:        "t",
+			wantWarning: `warning: command "app t" is deprecated: use "app get --check-tor" instead`,
+		},
+		{
+			name: "primary name of command with retired alias",
+			args: "sources",
+		},
+		{
+			name:        "retired alias",
+			args:        "src",
+			wantWarning: `warning: command "app src" is deprecated: use "app sources" instead`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			if err := app.handleSubCmd("app", splitTrimSpace(tt.args, " ")); err != nil {
+				t.Fatalf("error: got %q, want nil", err)
+			}
+			if got := out.String(); !strings.Contains(got, tt.wantWarning) || (tt.wantWarning == "" && got != "") {
+				t.Errorf("warning: got %q, want %q", got, tt.wantWarning)
+			}
+		})
+	}
+}
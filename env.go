@@ -0,0 +1,43 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Env option binds the flag to the environment variable name:
+// if the variable is set, its value overrides the default value of the flag.
+// The value passed in the command line overrides the environment one.
+// An invalid value of the variable, including a value not accepted
+// by the Enum option, is ignored with a warning.
+func Env(name string) FlagOption {
+	return func(fi *flagInfo) {
+		fi.env = name
+	}
+}
+
+// applyEnv sets the value of the flag from its environment variable, if set.
+func (fi *flagInfo) applyEnv(fs *flag.FlagSet) {
+	value, ok := os.LookupEnv(fi.env)
+	if !ok {
+		return
+	}
+	v := unwrapValue(fs.Lookup(fi.primary()).Value)
+	prev := v.String()
+	err := fi.checkEnum(v, value)
+	if err == nil {
+		if err = v.Set(value); err != nil {
+			// the value could be changed by a failed Set
+			v.Set(prev)
+		}
+	}
+	if err != nil {
+		if fi.secret {
+			err = ErrInvalidSecret
+			value = redact(value)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "warning: invalid value %q of environment variable %s for flag %s: %v\n",
+			value, fi.env, flagArg(fi.primary()), err)
+	}
+}
@@ -0,0 +1,109 @@
+package flagx
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_Env(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		args        []string
+		wantValue   int
+		wantWarning string
+	}{
+		{
+			name:      "env not set",
+			wantValue: 1,
+		},
+		{
+			name:      "env set",
+			env:       "4",
+			wantValue: 4,
+		},
+		{
+			name:      "command line overrides env",
+			env:       "4",
+			args:      []string{"-w", "8"},
+			wantValue: 8,
+		},
+		{
+			name:        "invalid env",
+			env:         "four",
+			wantValue:   1,
+			wantWarning: `warning: invalid value "four" of environment variable TEST_WORKERS for flag --workers`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_WORKERS", tt.env)
+			}
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			var workers int
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers", Env("TEST_WORKERS"))
+
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("error: got %q, want nil", err)
+			}
+			if workers != tt.wantValue {
+				t.Errorf("value: got %v, want %v", workers, tt.wantValue)
+			}
+			if got := out.String(); !strings.Contains(got, tt.wantWarning) {
+				t.Errorf("warning: got %q, want %q", got, tt.wantWarning)
+			}
+			if fs.Lookup("workers").DefValue != "1" {
+				t.Errorf("default value: got %q, want %q", fs.Lookup("workers").DefValue, "1")
+			}
+		})
+	}
+}
+
+func Test_EnvEnum(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		wantValue   string
+		wantWarning string
+	}{
+		{
+			name:      "accepted value",
+			env:       "A",
+			wantValue: "A",
+		},
+		{
+			name:        "value not accepted",
+			env:         "bogus",
+			wantValue:   "1",
+			wantWarning: `warning: invalid value "bogus" of environment variable TEST_PMODE for flag --mode: invalid value "bogus": accepted values are 1, A`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_PMODE", tt.env)
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			var mode string
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			AliasedStringVar(fs, &mode, "mode,m", "1", "result mode", Enum("1", "A"), Env("TEST_PMODE"))
+
+			if err := fs.Parse(nil); err != nil {
+				t.Fatalf("error: got %q, want nil", err)
+			}
+			if mode != tt.wantValue {
+				t.Errorf("value: got %q, want %q", mode, tt.wantValue)
+			}
+			if got := out.String(); !strings.Contains(got, tt.wantWarning) {
+				t.Errorf("warning: got %q, want %q", got, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package flagx
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 )
 
@@ -39,3 +41,109 @@ func wrapErrorf(err error, format string, a ...interface{}) error {
 	}
 	return &simpleflagError{fmt.Sprintf(format, a...), err}
 }
+
+// Process exit codes returned by ExitCode.
+const (
+	ExitOK          = 0   // success
+	ExitFailure     = 1   // generic failure
+	ExitUsage       = 2   // usage error: unknown command, invalid flags, ...
+	ExitTimeout     = 124 // the command timed out
+	ExitInterrupted = 130 // the command was interrupted by a signal
+)
+
+// Error is the error returned by flagx for the errors of the command line,
+// detected while dispatching the arguments to the commands or parsing the flags.
+// errors.Is(err, kind) reports whether err is an Error of the given kind.
+type Error struct {
+	Path        string   // full name of the command (example: "app get")
+	Token       string   // offending argument, if any (example: the unknown command name)
+	Kind        error    // kind of the error: ErrCommandNotFound, ErrFlagParse, ...
+	Err         error    // underlying error, if any
+	Suggestions []string // names similar to Token, if any
+	Printed     bool     // the message was already printed, by the flag set of the command
+	msg         string
+}
+
+// isPrinted checks if err is an *Error already printed.
+func isPrinted(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Printed
+}
+
+// newError returns an Error with "path: kind "token"" message,
+// followed by the suggestions, if any.
+// If token is empty, the message is "path: kind".
+func newError(kind error, path, token string, suggestions ...string) *Error {
+	msg := path + ": " + kind.Error()
+	if token != "" {
+		msg += fmt.Sprintf(" %q", token)
+	}
+	return &Error{
+		Path:        path,
+		Token:       token,
+		Kind:        kind,
+		Suggestions: suggestions,
+		msg:         msg + didYouMean(suggestions),
+	}
+}
+
+func (e *Error) Error() string { return e.msg }
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether the error is of the kind target.
+func (e *Error) Is(target error) bool { return target == e.Kind }
+
+// ExitCode returns the process exit code of the error: ExitUsage for
+// the usage errors, ExitTimeout for ErrTimeout, ExitInterrupted for ErrInterrupted,
+// ExitFailure otherwise.
+func (e *Error) ExitCode() int {
+	for _, ec := range exitCodes {
+		if e.Kind == ec.kind {
+			return ec.code
+		}
+	}
+	return ExitFailure
+}
+
+// exitCodes are the exit codes of the kinds of errors, other than ExitFailure.
+var exitCodes = []struct {
+	kind error
+	code int
+}{
+	{ErrCommandNotFound, ExitUsage},
+	{ErrTopicNotFound, ExitUsage},
+	{ErrNoExecFunc, ExitUsage},
+	{ErrFlagParse, ExitUsage},
+	{ErrRequiredFlag, ExitUsage},
+	{ErrInvalidEnum, ExitUsage},
+	{ErrInvalidAlias, ExitUsage},
+	{ErrAliasLoop, ExitUsage},
+	{ErrTimeout, ExitTimeout},
+	{ErrInterrupted, ExitInterrupted},
+}
+
+// ExitCode returns the process exit code of the error err:
+//
+//   - ExitOK if err is nil or flag.ErrHelp;
+//   - the code returned by the ExitCode method of the first error
+//     in the chain of err that has one (for example an *Error);
+//   - the exit code of the kind of err, if it is a flagx error
+//     (see the ExitCode method of Error);
+//   - ExitFailure otherwise.
+func ExitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	var ec interface{ ExitCode() int }
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+	for _, ec := range exitCodes {
+		if errors.Is(err, ec.kind) {
+			return ec.code
+		}
+	}
+	return ExitFailure
+}
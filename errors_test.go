@@ -2,6 +2,10 @@ package flagx
 
 import (
 	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -98,3 +102,124 @@ func Test_wrapErrorf(t *testing.T) {
 		})
 	}
 }
+
+// parseFlags is a ParseExec that parses the flags by fs.Parse,
+// printing the errors to Stderr.
+func parseFlags(fullname string, arguments []string) error {
+	fs := flag.NewFlagSet(fullname, flag.ContinueOnError)
+	fs.SetOutput(Stderr)
+	fs.Usage = func() {}
+	fs.Int("w", 1, "number of workers")
+	fs.Bool("n", false, "dry run")
+	return fs.Parse(arguments)
+}
+
+func TestError(t *testing.T) {
+	oldStderr := Stderr
+	defer func() { Stderr = oldStderr }()
+	Stderr = io.Discard
+
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get":   {},
+			"parse": {ParseExec: parseFlags},
+		},
+	}
+	tests := []struct {
+		name      string
+		args      []string
+		kind      error
+		path      string
+		token     string
+		exitCode  int
+		wantErrIs error
+	}{
+		{"not-found", []string{"gte"}, ErrCommandNotFound, "app", "gte", ExitUsage, ErrCommandNotFound},
+		{"no-exec", []string{"get"}, ErrNoExecFunc, "app get", "", ExitUsage, ErrNoExecFunc},
+		{"flag-undefined", []string{"parse", "-x"}, ErrFlagParse, "app parse", "-x", ExitUsage, ErrFlagParse},
+		{"flag-value", []string{"parse", "-w", "many"}, ErrFlagParse, "app parse", "-w", ExitUsage, ErrFlagParse},
+		{"flag-bool", []string{"parse", "-n=maybe"}, ErrFlagParse, "app parse", "-n", ExitUsage, ErrFlagParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.run("app", tt.args)
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("want *Error, got %T", err)
+			}
+			if e.Kind != tt.kind || e.Path != tt.path || e.Token != tt.token {
+				t.Errorf("got {%v %q %q}, want {%v %q %q}", e.Kind, e.Path, e.Token, tt.kind, tt.path, tt.token)
+			}
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("errors.Is(%v) = false", tt.wantErrIs)
+			}
+			if got := ExitCode(err); got != tt.exitCode {
+				t.Errorf("ExitCode = %d, want %d", got, tt.exitCode)
+			}
+		})
+	}
+
+	e := newError(ErrCommandNotFound, "app", "gte", "get")
+	if want := `app: command not found "gte" (did you mean "get"?)`; e.Error() != want {
+		t.Errorf("Error() = %q, want %q", e.Error(), want)
+	}
+}
+
+type exitCodeError int
+
+func (e exitCodeError) Error() string { return "exit code error" }
+func (e exitCodeError) ExitCode() int { return int(e) }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"help", flag.ErrHelp, ExitOK},
+		{"generic", errors.New("generic"), ExitFailure},
+		{"invalid-name", newError(ErrInvalidCommandName, "app", ""), ExitFailure},
+		{"wrapped-usage", wrapNameError(ErrCommandNotFound, "app"), ExitUsage},
+		{"enum", wrapErrorf(ErrInvalidEnum, "x"), ExitUsage},
+		{"exit-coder", wrapNameError(exitCodeError(3), "app"), 3},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: ExitCode = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunMain(t *testing.T) {
+	oldExit, oldStderr, oldArgs := exit, Stderr, os.Args
+	defer func() { exit, Stderr, os.Args = oldExit, oldStderr, oldArgs }()
+
+	var code int
+	exit = func(c int) { code = c }
+	var buf strings.Builder
+	Stderr = &buf
+	os.Args = []string{"/bin/app", "gte"}
+
+	RunMain(&Command{SubCmd: map[string]*Command{"get": {}}})
+
+	if code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+	if got, want := buf.String(), "error: app: command not found \"gte\" (did you mean \"get\"?)\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+
+	// the parse error is printed only by the flag set
+	buf.Reset()
+	os.Args = []string{"/bin/app", "parse", "-x"}
+
+	RunMain(&Command{SubCmd: map[string]*Command{"parse": {ParseExec: parseFlags}}})
+
+	if code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+	if got, want := buf.String(), "flag provided but not defined: -x\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
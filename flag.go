@@ -9,66 +9,76 @@ import (
 // IsPassed checks if flag was provided.
 // It returns true if at least one alias of the flag was found.
 // names is the comma separated aliases of the flag.
-
+// The flags defined by the options of a flag, like the "no-<name>" flags
+// of a Negatable flag, are considered aliases of the flag.
 func IsPassed(fs *flag.FlagSet, names string) bool {
 	found := false
 
+	anames := splitTrimSpace(names, ",")
 	fs.Visit(func(f *flag.Flag) {
-		anames := splitTrimSpace(names, ",")
-		for _, name := range anames {
-			if f.Name == name {
-				found = true
-				return
-			}
+		if contains(anames, f.Name) {
+			found = true
+			return
+		}
+		if dv, ok := f.Value.(derivedValue); ok && contains(anames, dv.derivedFrom()) {
+			found = true
 		}
 	})
 	return found
 }
 
-// AliasedStringVar defines a string flag with specified names, default value, and usage string.
-// The `names` argument is the comma separated aliases of the flag.
-// The specified usage string is used for the primary flag name only.
-// The usage string of a secondary flag name specifies that it is an alias of the primary name.
-// The argument p points to a string variable in which to store the value of the flag.
-func AliasedStringVar(fs *flag.FlagSet, p *string, names string, value string, usage string) {
+// aliasUsage returns the usage string of a secondary flag name.
+func aliasUsage(primary string) string {
+	return "alias of \"" + primary + "\""
+}
+
+// aliasedVar defines a flag for each of the comma separated `names`.
+// The define function is called to define the flag with the given name and usage.
+// The specified usage string is used for the primary flag name only;
+// the usage string of a secondary flag name specifies that it is an alias of the primary name.
+// Finally the options are applied to the defined flags.
+func aliasedVar(fs *flag.FlagSet, names string, usage string, opts []FlagOption, define func(name, usage string)) {
 	anames := splitTrimSpace(names, ",")
 	for j, name := range anames {
 		if j == 1 {
 			// redefine usage for the aliased names
-			usage = "alias of \"" + anames[0] + "\""
+			usage = aliasUsage(anames[0])
 		}
-		fs.StringVar(p, name, value, usage)
+		define(name, usage)
 	}
+	newFlagInfo(anames, opts).apply(fs)
+}
+
+// AliasedStringVar defines a string flag with specified names, default value, and usage string.
+// The `names` argument is the comma separated aliases of the flag.
+// The specified usage string is used for the primary flag name only.
+// The usage string of a secondary flag name specifies that it is an alias of the primary name.
+// The argument p points to a string variable in which to store the value of the flag.
+func AliasedStringVar(fs *flag.FlagSet, p *string, names string, value string, usage string, opts ...FlagOption) {
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
+		fs.StringVar(p, name, value, usage)
+	})
 }
 
 // AliasedIntVar defines an int flag with specified names, default value, and usage string.
 // The specified usage string is used for the primary flag name only.
 // The usage string of a secondary flag name specifies that it is an alias of the primary name.
 // The argument p points to an int variable in which to store the value of the flag.
-func AliasedIntVar(fs *flag.FlagSet, p *int, names string, value int, usage string) {
-	anames := splitTrimSpace(names, ",")
-	for j, name := range anames {
-		if j == 1 {
-			// redefine usage for the aliased names
-			usage = "alias of \"" + anames[0] + "\""
-		}
+func AliasedIntVar(fs *flag.FlagSet, p *int, names string, value int, usage string, opts ...FlagOption) {
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
 		fs.IntVar(p, name, value, usage)
-	}
+	})
 }
 
 // AliasedBoolVar defines a bool flag with specified names, default value, and usage string.
 // The specified usage string is used for the primary flag name only.
 // The usage string of a secondary flag name specifies that it is an alias of the primary name.
 // The argument p points to a bool variable in which to store the value of the flag.
-func AliasedBoolVar(fs *flag.FlagSet, p *bool, names string, value bool, usage string) {
-	anames := splitTrimSpace(names, ",")
-	for j, name := range anames {
-		if j == 1 {
-			// redefine usage for the aliased names
-			usage = "alias of \"" + anames[0] + "\""
-		}
+// Use the Negatable option to define also the "no-<name>" flags.
+func AliasedBoolVar(fs *flag.FlagSet, p *bool, names string, value bool, usage string, opts ...FlagOption) {
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
 		fs.BoolVar(p, name, value, usage)
-	}
+	})
 }
 
 // astring type is an array of string implementing the flag.Value interface.
@@ -94,44 +104,29 @@ func (o *astring) Set(value string) error {
 // The usage string of a secondary flag name specifies that it is an alias of the primary name.
 // The argument p points to a []string variable in which to store the value of the flag.
 // Note: no default value is given.
-func AliasedStringsVar(fs *flag.FlagSet, p *[]string, names string, usage string) {
+func AliasedStringsVar(fs *flag.FlagSet, p *[]string, names string, usage string, opts ...FlagOption) {
 	var ss *astring = (*astring)(p)
-	anames := splitTrimSpace(names, ",")
-	for j, name := range anames {
-		if j == 1 {
-			// redefine usage for the aliased names
-			usage = "alias of \"" + anames[0] + "\""
-		}
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
 		fs.Var(ss, name, usage)
-	}
+	})
 }
 
 // AliasedInt64Var defines an int64 flag with specified names, default value, and usage string.
 // The specified usage string is used for the primary flag name only.
 // The usage string of a secondary flag name specifies that it is an alias of the primary name.
 // The argument p points to an int64 variable in which to store the value of the flag.
-func AliasedInt64Var(fs *flag.FlagSet, p *int64, names string, value int64, usage string) {
-	anames := splitTrimSpace(names, ",")
-	for j, name := range anames {
-		if j == 1 {
-			// redefine usage for the aliased names
-			usage = "alias of \"" + anames[0] + "\""
-		}
+func AliasedInt64Var(fs *flag.FlagSet, p *int64, names string, value int64, usage string, opts ...FlagOption) {
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
 		fs.Int64Var(p, name, value, usage)
-	}
+	})
 }
 
 // AliasedFloat64Var defines an float64 flag with specified names, default value, and usage string.
 // The specified usage string is used for the primary flag name only.
 // The usage string of a secondary flag name specifies that it is an alias of the primary name.
 // The argument p points to an float64 variable in which to store the value of the flag.
-func AliasedFloat64Var(fs *flag.FlagSet, p *float64, names string, value float64, usage string) {
-	anames := splitTrimSpace(names, ",")
-	for j, name := range anames {
-		if j == 1 {
-			// redefine usage for the aliased names
-			usage = "alias of \"" + anames[0] + "\""
-		}
+func AliasedFloat64Var(fs *flag.FlagSet, p *float64, names string, value float64, usage string, opts ...FlagOption) {
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
 		fs.Float64Var(p, name, value, usage)
-	}
+	})
 }
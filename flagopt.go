@@ -0,0 +1,222 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlagOption configures an aliased flag defined by the Aliased*Var functions.
+type FlagOption func(fi *flagInfo)
+
+// flagInfo holds the flagx specific attributes of an aliased flag.
+type flagInfo struct {
+	names      []string // primary name followed by the aliases
+	negatable  bool     // define the "no-<name>" flags
+	secret     bool     // hide the value of the flag
+	secretFile bool     // define the "<name>-file" flag
+	fromFile   bool     // read the value from a file or stdin
+	env        string   // environment variable bound to the flag
+	required   bool     // the flag must be passed
+	enum       []string // accepted values
+
+	hidden         bool     // hide the flag
+	hiddenAliases  []string // hidden aliases
+	deprecated     string   // deprecation message
+	retiredAliases []string // retired aliases
+}
+
+// newFlagInfo returns the flagInfo of the flag with the given names
+// and the options applied.
+func newFlagInfo(names []string, opts []FlagOption) *flagInfo {
+	fi := &flagInfo{names: names}
+	for _, opt := range opts {
+		opt(fi)
+	}
+	return fi
+}
+
+// primary returns the primary name of the flag.
+func (fi *flagInfo) primary() string {
+	if len(fi.names) == 0 {
+		return ""
+	}
+	return fi.names[0]
+}
+
+// wrapped checks if the values of the flag must be wrapped by an optValue.
+// The hidden, env and required attributes don't change the behaviour of the value,
+// but they are wrapped anyway to be found by the usage functions.
+func (fi *flagInfo) wrapped() bool {
+	return fi.secret || fi.fromFile || fi.env != "" || fi.required || len(fi.enum) > 0 || fi.deprecated != "" || len(fi.retiredAliases) > 0 ||
+		fi.hidden || len(fi.hiddenAliases) > 0
+}
+
+// apply applies the options to the flags already defined in the flag set.
+func (fi *flagInfo) apply(fs *flag.FlagSet) {
+	if fi.wrapped() {
+		for _, name := range fi.names {
+			f := fs.Lookup(name)
+			f.Value = &optValue{name: name, info: fi, value: f.Value}
+			if fi.secret && !isZeroDefault(f.DefValue) {
+				// a zero default is not a secret
+				f.DefValue = Redacted
+			}
+		}
+	}
+
+	if fi.env != "" {
+		fi.applyEnv(fs)
+	}
+
+	if fi.negatable {
+		for _, name := range fi.names {
+			f := fs.Lookup(name)
+			if !isBoolValue(f.Value) {
+				panic(fmt.Sprintf("flagx: Negatable option used with non-boolean flag %q", name))
+			}
+			fs.Var(&negatedValue{fi.primary(), f.Value}, negatedName(name), negationUsage(fi.primary()))
+			// the negated flag is a trigger: its default is always false
+			fs.Lookup(negatedName(name)).DefValue = "false"
+		}
+	}
+
+	if fi.secretFile {
+		f := fs.Lookup(fi.primary())
+		fs.Var(&secretFileValue{name: fi.primary(), value: f.Value}, secretFileName(fi.primary()), secretFileUsage(fi.primary()))
+	}
+}
+
+// Negatable option defines, for each name of a boolean flag,
+// the "no-<name>" flag that sets the value of the flag to false.
+// It panics if used with a non-boolean flag.
+func Negatable() FlagOption {
+	return func(fi *flagInfo) {
+		fi.negatable = true
+	}
+}
+
+// negatedName returns the name of the negated flag.
+func negatedName(name string) string {
+	return "no-" + name
+}
+
+// negationUsage returns the usage string of a negated flag name.
+func negationUsage(primary string) string {
+	return "negation of \"" + primary + "\""
+}
+
+// isBoolValue checks if the value is a boolean flag value.
+func isBoolValue(v flag.Value) bool {
+	bv, ok := v.(interface{ IsBoolFlag() bool })
+	return ok && bv.IsBoolFlag()
+}
+
+// derivedValue is implemented by the values of the flags defined by flagx
+// on behalf of another flag, like the "no-<name>" flags.
+// Passing a derived flag counts as passing the original flag.
+type derivedValue interface {
+	// derivedFrom returns the primary name of the original flag.
+	derivedFrom() string
+}
+
+// negatedValue is the flag.Value of a "no-<name>" flag.
+// Setting it to true sets the negated flag to false, and vice versa.
+type negatedValue struct {
+	name  string     // primary name of the negated flag
+	value flag.Value // value of the negated flag
+}
+
+// derivedFrom returns the primary name of the negated flag.
+func (v *negatedValue) derivedFrom() string { return v.name }
+
+// String method of flag.Value interface.
+func (v *negatedValue) String() string {
+	if v.value == nil {
+		return "false"
+	}
+	b, err := strconv.ParseBool(v.value.String())
+	if err != nil {
+		return "false"
+	}
+	return strconv.FormatBool(!b)
+}
+
+// Set method of flag.Value interface.
+func (v *negatedValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	return v.value.Set(strconv.FormatBool(!b))
+}
+
+// IsBoolFlag marks the negated flag as a boolean flag.
+func (v *negatedValue) IsBoolFlag() bool { return true }
+
+// optValue wraps the flag.Value of a flag name
+// to implement the behaviour of the flag options.
+type optValue struct {
+	name  string     // name of the flag
+	info  *flagInfo  // attributes of the aliased flag
+	value flag.Value // wrapped value
+}
+
+// unwrapValue returns the value wrapped by an optValue,
+// or the value itself if not wrapped.
+func unwrapValue(v flag.Value) flag.Value {
+	if ov, ok := v.(*optValue); ok && ov.value != nil {
+		return ov.value
+	}
+	return v
+}
+
+// String method of flag.Value interface.
+func (v *optValue) String() string {
+	if v.value == nil {
+		return ""
+	}
+	s := v.value.String()
+	if v.info.secret {
+		s = redact(s)
+	}
+	return s
+}
+
+// Set method of flag.Value interface.
+func (v *optValue) Set(s string) error {
+	v.info.warnDeprecatedFlag(v.name)
+	if v.info.fromFile {
+		if strings.HasPrefix(s, "@@") {
+			s = s[1:]
+		} else if ok, err := setFromFile(v, s); ok {
+			return err
+		}
+	}
+	return v.set(s)
+}
+
+// set sets the wrapped value, checking the accepted values.
+func (v *optValue) set(s string) error {
+	if err := v.info.checkEnum(v.value, s); err != nil {
+		return err
+	}
+	err := v.value.Set(s)
+	if err != nil && v.info.secret {
+		err = ErrInvalidSecret
+	}
+	return err
+}
+
+// Get method of flag.Getter interface.
+// It returns nil if the wrapped value does not implement flag.Getter.
+func (v *optValue) Get() interface{} {
+	if g, ok := v.value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return nil
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag value.
+func (v *optValue) IsBoolFlag() bool { return isBoolValue(v.value) }
@@ -0,0 +1,98 @@
+package flagx
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_Negatable(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		value      bool
+		args       string
+		wantPassed bool
+		wantValue  bool
+	}{
+		{
+			name:       "empty command line",
+			value:      true,
+			args:       "",
+			wantPassed: false,
+			wantValue:  true,
+		},
+		{
+			name:       "primary name",
+			value:      false,
+			args:       "--dry-run",
+			wantPassed: true,
+			wantValue:  true,
+		},
+		{
+			name:       "negated primary name",
+			value:      true,
+			args:       "--no-dry-run",
+			wantPassed: true,
+			wantValue:  false,
+		},
+		{
+			name:       "negated alias",
+			value:      true,
+			args:       "-no-n",
+			wantPassed: true,
+			wantValue:  false,
+		},
+		{
+			name:       "negated with explicit false",
+			value:      false,
+			args:       "--no-dry-run=false",
+			wantPassed: true,
+			wantValue:  true,
+		},
+		{
+			name:       "last wins",
+			value:      false,
+			args:       "--no-dry-run -n",
+			wantPassed: true,
+			wantValue:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value bool
+
+			var out strings.Builder
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&out)
+
+			AliasedBoolVar(fs, &value, "dry-run,n", tt.value, "usage dry-run", Negatable())
+
+			args := splitTrimSpace(tt.args, " ")
+			if err := fs.Parse(args); err != nil {
+				t.Errorf("error: got %q, want nil", err)
+				return
+			}
+
+			if got := IsPassed(fs, "dry-run"); got != tt.wantPassed {
+				t.Errorf("IsPassed: got %v, want %v", got, tt.wantPassed)
+			}
+			if value != tt.wantValue {
+				t.Errorf("value: got %v, want %v", value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_Negatable_NonBool(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Negatable on int flag: got no panic")
+		}
+	}()
+
+	var value int
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	AliasedIntVar(fs, &value, "int,i", 0, "usage int", Negatable())
+}
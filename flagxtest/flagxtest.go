@@ -0,0 +1,247 @@
+// Package flagxtest provides utilities for testing the command line
+// applications built with flagx.
+//
+// A Runner executes the root command of an application with the given
+// arguments, environment and standard input, and returns a Result with the
+// captured standard output and error, the error and the exit code:
+//
+//	r := &flagxtest.Runner{App: app, Name: "app"}
+//	res := r.RunString(t, "get -n 'isin 1'")
+//	if res.ExitCode != 0 {
+//		t.Fatalf("exit code %d: %s", res.ExitCode, res.Stderr)
+//	}
+//
+// The Runner redefines global state (os.Args, os.Stdin, os.Stdout, os.Stderr,
+// the output of flag.CommandLine and the flagx Stdin, Stdout, Stderr and
+// HelpWidth) during the execution, so it must not be used by parallel tests.
+package flagxtest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	flagx "github.com/mmbros/flagx"
+)
+
+// DefaultName is the default name of the root command of a Runner.
+const DefaultName = "app"
+
+// Runner executes the root command of a flagx application.
+type Runner struct {
+	App   *flagx.Command    // root command
+	Name  string            // name of the root command; default DefaultName
+	Env   map[string]string // environment variables, set with t.Setenv
+	Stdin string            // standard input of the command
+	Width int               // width of the help; default flagx.DefaultHelpWidth
+}
+
+// Result is the result of the execution of a command.
+type Result struct {
+	Stdout   string // captured standard output
+	Stderr   string // captured standard error, including the help and the printed error
+	Err      error  // error returned by the command
+	ExitCode int    // exit code of the command (see flagx.ExitCode)
+}
+
+// Run executes the root command with the given arguments, not including
+// the name of the command. As flagx.RunMain, the error is printed to the
+// standard error, unless it is a help request, the exit of a plugin
+// or an error already printed by the flag set (see flagx.Error).
+// The environment variables of the runner are set with t.Setenv,
+// and are restored at the end of the test.
+func (r *Runner) Run(t testing.TB, args ...string) *Result {
+	t.Helper()
+
+	for k, v := range r.Env {
+		t.Setenv(k, v)
+	}
+	name := r.Name
+	if name == "" {
+		name = DefaultName
+	}
+	width := r.Width
+	if width <= 0 {
+		width = flagx.DefaultHelpWidth
+	}
+
+	stdout, err := newCapture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := newCapture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin, err := newInput(r.Stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(name, args, width, stdin, stdout.w, stderr.w, r.App)
+	stdin.Close()
+
+	return &Result{
+		Stdout:   stdout.close(),
+		Stderr:   stderr.close(),
+		Err:      err,
+		ExitCode: flagx.ExitCode(err),
+	}
+}
+
+// RunString executes the root command with the arguments of the command line
+// cmdline, not including the name of the command, split with shell-like rules
+// (see flagx.Split). A malformed command line fails the test.
+func (r *Runner) RunString(t testing.TB, cmdline string) *Result {
+	t.Helper()
+
+	args, err := flagx.Split(cmdline)
+	if err != nil {
+		t.Fatalf("command line %q: %v", cmdline, err)
+	}
+	return r.Run(t, args...)
+}
+
+// run executes the root command app, redefining the global state
+// during the execution, and restoring it before returning.
+func run(name string, args []string, width int, stdin, stdout, stderr *os.File, app *flagx.Command) error {
+	oldArgs, oldStdin, oldStdout, oldStderr := os.Args, os.Stdin, os.Stdout, os.Stderr
+	oldxStdin, oldxStdout, oldxStderr := flagx.Stdin, flagx.Stdout, flagx.Stderr
+	oldOutput, oldWidth := flag.CommandLine.Output(), flagx.HelpWidth
+	defer func() {
+		os.Args, os.Stdin, os.Stdout, os.Stderr = oldArgs, oldStdin, oldStdout, oldStderr
+		flagx.Stdin, flagx.Stdout, flagx.Stderr = oldxStdin, oldxStdout, oldxStderr
+		flag.CommandLine.SetOutput(oldOutput)
+		flagx.HelpWidth = oldWidth
+	}()
+
+	os.Args = append([]string{name}, args...)
+	os.Stdin, os.Stdout, os.Stderr = stdin, stdout, stderr
+	flagx.Stdin, flagx.Stdout, flagx.Stderr = stdin, stdout, stderr
+	flag.CommandLine.SetOutput(stderr)
+	flagx.HelpWidth = width
+
+	err := flagx.Run(app)
+	var pe *flagx.PluginExitError
+	var fe *flagx.Error
+	if err != nil && !errors.Is(err, flag.ErrHelp) && !errors.As(err, &pe) && !(errors.As(err, &fe) && fe.Printed) {
+		flagx.PrintError(stderr, err)
+	}
+	return err
+}
+
+// capture collects the data written to a pipe.
+type capture struct {
+	w    *os.File
+	buf  bytes.Buffer
+	done chan struct{}
+}
+
+// newCapture returns a capture of the data written to its w file.
+func newCapture() (*capture, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	c := &capture{w: pw, done: make(chan struct{})}
+	go func() {
+		io.Copy(&c.buf, pr)
+		pr.Close()
+		close(c.done)
+	}()
+	return c, nil
+}
+
+// close closes the w file and returns the data written to it.
+func (c *capture) close() string {
+	c.w.Close()
+	<-c.done
+	return c.buf.String()
+}
+
+// newInput returns a file that reads the string s. The file must be closed
+// by the caller, also if it is not read entirely.
+func newInput(s string) (*os.File, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		io.WriteString(pw, s)
+		pw.Close()
+	}()
+	return pr, nil
+}
+
+// AssertOutput checks that the output got is equal to want,
+// reporting the first different line.
+func AssertOutput(t testing.TB, got, want string) {
+	t.Helper()
+
+	if got == want {
+		return
+	}
+	gl, wl := strings.Split(got, "\n"), strings.Split(want, "\n")
+	j := 0
+	for j < len(gl) && j < len(wl) && gl[j] == wl[j] {
+		j++
+	}
+	line := func(ls []string) string {
+		if j < len(ls) {
+			return fmt.Sprintf("%q", ls[j])
+		}
+		return "<end of output>"
+	}
+	t.Errorf("output differs at line %d:\n got: %s\nwant: %s\n--- got\n%s\n--- want\n%s",
+		j+1, line(gl), line(wl), got, want)
+}
+
+// AssertHelp checks that the result is a help request, with exit code 0,
+// and that the help printed is equal to want.
+func AssertHelp(t testing.TB, res *Result, want string) {
+	t.Helper()
+
+	if !errors.Is(res.Err, flag.ErrHelp) {
+		t.Errorf("error: got %v, want help request", res.Err)
+	}
+	if res.ExitCode != flagx.ExitOK {
+		t.Errorf("exit code: got %d, want %d", res.ExitCode, flagx.ExitOK)
+	}
+	AssertOutput(t, res.Stderr, want)
+}
+
+// AssertOptions checks that the options got, usually a pointer to the struct
+// of the parsed options, are deeply equal to want, reporting the different
+// fields of the struct.
+func AssertOptions(t testing.TB, got, want interface{}) {
+	t.Helper()
+
+	if reflect.DeepEqual(got, want) {
+		return
+	}
+	gv, wv := reflect.ValueOf(got), reflect.ValueOf(want)
+	for gv.Kind() == reflect.Ptr && wv.Kind() == reflect.Ptr && !gv.IsNil() && !wv.IsNil() {
+		gv, wv = gv.Elem(), wv.Elem()
+	}
+	if gv.Kind() != reflect.Struct || gv.Type() != wv.Type() {
+		t.Errorf("options: got %+v, want %+v", got, want)
+		return
+	}
+	reported := false
+	for j := 0; j < gv.NumField(); j++ {
+		g, w := fmt.Sprintf("%#v", gv.Field(j)), fmt.Sprintf("%#v", wv.Field(j))
+		if g != w {
+			t.Errorf("options %s: got %s, want %s", gv.Type().Field(j).Name, g, w)
+			reported = true
+		}
+	}
+	if !reported {
+		t.Errorf("options: got %+v, want %+v", got, want)
+	}
+}
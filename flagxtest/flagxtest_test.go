@@ -0,0 +1,197 @@
+package flagxtest_test
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	flagx "github.com/mmbros/flagx"
+	"github.com/mmbros/flagx/flagxtest"
+)
+
+type options struct {
+	workers int
+	isins   []string
+	dryRun  bool
+}
+
+var opt options
+
+func newApp() *flagx.Command {
+	opt = options{}
+	return &flagx.Command{
+		SubCmd: map[string]*flagx.Command{
+			"get,g": {
+				Short:     "Get the quotes of the isins",
+				ParseExec: runGet,
+			},
+			"echo": {
+				Short:     "Copy the standard input to the output",
+				ParseExec: runEcho,
+			},
+		},
+	}
+}
+
+func runGet(name string, arguments []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-w n] [-n] isin...\n", name)
+	}
+	fs.IntVar(&opt.workers, "w", 1, "number of workers")
+	fs.BoolVar(&opt.dryRun, "n", false, "dry run")
+	if err := flagx.Parse(fs, arguments); err != nil {
+		return err
+	}
+	opt.isins = fs.Args()
+	for _, isin := range opt.isins {
+		fmt.Println(isin)
+	}
+	return nil
+}
+
+func runEcho(name string, arguments []string) error {
+	sc := bufio.NewScanner(os.Stdin)
+	for sc.Scan() {
+		fmt.Fprintf(flagx.Stdout, "%s%s\n", os.Getenv("ECHO_PREFIX"), sc.Text())
+	}
+	return sc.Err()
+}
+
+func TestRunner_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		env        map[string]string
+		stdin      string
+		wantStdout string
+		wantStderr string
+		wantErr    error
+		wantCode   int
+	}{
+		{
+			name:       "output",
+			args:       "get -w 4 'isin 1' isin2",
+			wantStdout: "isin 1\nisin2\n",
+		},
+		{
+			name:       "help",
+			args:       "g -h",
+			wantStderr: "usage: app get [-w n] [-n] isin...\n",
+			wantErr:    flag.ErrHelp,
+		},
+		{
+			name:       "command not found",
+			args:       "gte",
+			wantStderr: "error: app: command not found \"gte\" (did you mean \"get\"?)\n",
+			wantErr:    flagx.ErrCommandNotFound,
+			wantCode:   flagx.ExitUsage,
+		},
+		{
+			name:       "flag error",
+			args:       "get -x",
+			wantStderr: "flag provided but not defined: -x\nusage: app get [-w n] [-n] isin...\n",
+			wantErr:    flagx.ErrFlagParse,
+			wantCode:   flagx.ExitUsage,
+		},
+		{
+			name:       "stdin and env",
+			args:       "echo",
+			env:        map[string]string{"ECHO_PREFIX": "> "},
+			stdin:      "line 1\nline 2\n",
+			wantStdout: "> line 1\n> line 2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &flagxtest.Runner{App: newApp(), Env: tt.env, Stdin: tt.stdin}
+			res := r.RunString(t, tt.args)
+
+			if !errors.Is(res.Err, tt.wantErr) || (tt.wantErr == nil && res.Err != nil) {
+				t.Errorf("error: got %v, want %v", res.Err, tt.wantErr)
+			}
+			if res.ExitCode != tt.wantCode {
+				t.Errorf("exit code: got %d, want %d", res.ExitCode, tt.wantCode)
+			}
+			flagxtest.AssertOutput(t, res.Stdout, tt.wantStdout)
+			flagxtest.AssertOutput(t, res.Stderr, tt.wantStderr)
+		})
+	}
+}
+
+func TestRunner_restore(t *testing.T) {
+	args, stdout, output := os.Args, os.Stdout, flag.CommandLine.Output()
+
+	r := &flagxtest.Runner{App: newApp()}
+	r.Run(t, "get", "isin")
+
+	if !equalStrings(os.Args, args) || os.Stdout != stdout || flag.CommandLine.Output() != output {
+		t.Errorf("global state not restored")
+	}
+}
+
+func TestAssertHelp(t *testing.T) {
+	r := &flagxtest.Runner{App: newApp(), Name: "quotes", Width: 60}
+	res := r.Run(t, "-h")
+
+	flagxtest.AssertHelp(t, res, `Usage:
+    quotes <command> [options]
+
+Available commands:
+    echo  Copy the standard input to the output
+    get   Get the quotes of the isins
+`)
+}
+
+func TestAssertOptions(t *testing.T) {
+	r := &flagxtest.Runner{App: newApp()}
+	r.RunString(t, "get -w 8 -n a b")
+
+	flagxtest.AssertOptions(t, &opt, &options{workers: 8, isins: []string{"a", "b"}, dryRun: true})
+
+	ft := &fakeT{TB: t}
+	flagxtest.AssertOptions(ft, &opt, &options{workers: 4, isins: []string{"a", "b"}})
+	want := []string{
+		"options workers: got 8, want 4",
+		"options dryRun: got true, want false",
+	}
+	if !equalStrings(ft.errors, want) {
+		t.Errorf("errors: got %q, want %q", ft.errors, want)
+	}
+}
+
+func TestAssertOutput(t *testing.T) {
+	ft := &fakeT{TB: t}
+	flagxtest.AssertOutput(ft, "a\nb\nc\n", "a\nx\nc\n")
+	if len(ft.errors) != 1 || !strings.HasPrefix(ft.errors[0], "output differs at line 2:\n got: \"b\"\nwant: \"x\"\n") {
+		t.Errorf("errors: got %q", ft.errors)
+	}
+}
+
+// fakeT is a testing.TB that records the errors.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (ft *fakeT) Helper() {}
+
+func (ft *fakeT) Errorf(format string, args ...interface{}) {
+	ft.errors = append(ft.errors, fmt.Sprintf(format, args...))
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for j := range a {
+		if a[j] != b[j] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"sort"
+)
+
+// defaultGroup is the heading of the sub-commands without a group.
+const defaultGroup = "Available commands"
+
+// commandGroup is a group of sub-commands listed under the same heading.
+type commandGroup struct {
+	heading  string
+	commands []*subCommand
+}
+
+// groupSubCommands groups the sub-commands by Group.
+// The sub-commands without a group come first, followed by the other
+// groups sorted by heading. The order of the sub-commands is preserved.
+func groupSubCommands(scs []*subCommand) []*commandGroup {
+	groups := []*commandGroup{}
+	byHeading := map[string]*commandGroup{}
+
+	for _, sc := range scs {
+		heading := sc.cmd.Group
+		if heading == "" {
+			heading = defaultGroup
+		}
+		g := byHeading[heading]
+		if g == nil {
+			g = &commandGroup{heading: heading}
+			byHeading[heading] = g
+			groups = append(groups, g)
+		}
+		g.commands = append(g.commands, sc)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		gi, gj := groups[i].heading, groups[j].heading
+		if gi == defaultGroup || gj == defaultGroup {
+			return gi == defaultGroup && gj != defaultGroup
+		}
+		return gi < gj
+	})
+	return groups
+}
+
+// description returns the long description of the command,
+// or the short one if the long is not defined.
+func (cmd *Command) description() string {
+	if cmd.Long != "" {
+		return cmd.Long
+	}
+	return cmd.Short
+}
+
+// PrintHelp prints the help of the command cmd, with full name fullname, to w.
+// The help is generated by the HelpTemplate of the command, or by
+// DefaultHelpTemplate if not defined, executed with the HelpData of the command.
+// The default template prints the usage line, the description, the visible
+// sub-commands with their short descriptions grouped by Group, the help topics,
+// the positional arguments, the options defined by Flags and the examples.
+// For example:
+//
+//	Usage:
+//	    app <command> [options]
+//
+//	Available commands:
+//	    get      Get the quotes of the specified isins
+//	    sources  Show available sources
+func PrintHelp(w io.Writer, fullname string, cmd *Command) error {
+	return printHelp(w, fullname, nil, cmd)
+}
+
+// HelpUsage returns a function that prints the help of the command cmd,
+// with full name fullname, to the output of flag.CommandLine. Assigned to
+// the Usage of the flag set parsed by the command, it shows the generated
+// help, as the help command, also for the help arguments that follow other
+// flags. For example, in the ParseExec of the command cmd:
+//
+//	fs := flag.NewFlagSet(fullname, flag.ContinueOnError)
+//	fs.Usage = flagx.HelpUsage(fullname, cmd)
+func HelpUsage(fullname string, cmd *Command) func() {
+	return func() {
+		printHelp(flag.CommandLine.Output(), fullname, nil, cmd)
+	}
+}
+
+// printHelp prints the help of the command cmd, with full name fullname
+// and visible names in the parent command (nil for the root command), to w.
+func printHelp(w io.Writer, fullname string, names []string, cmd *Command) error {
+	data, err := newHelpData(fullname, names, cmd, helpWidth(w))
+	if err != nil {
+		return err
+	}
+	data.Color = useColor(w)
+	return data.execute(w, cmd.HelpTemplate)
+}
+
+// flagDefs returns the visible flag definitions of the command,
+// defined by Flags in a new flag set.
+func (cmd *Command) flagDefs(fullname string) []*flagDef {
+	return visibleFlagDefs(cmd.allFlagDefs(fullname))
+}
+
+// allFlagDefs returns all the flag definitions of the command, hidden included,
+// defined by Flags in a new flag set.
+// The "--timeout" and batch flags handled by flagx are included, if defined.
+func (cmd *Command) allFlagDefs(fullname string) []*flagDef {
+	hasTimeout := cmd.hasTimeoutFlag()
+	if cmd.Flags == nil && !hasTimeout && !cmd.Batch {
+		return nil
+	}
+	fs := flag.NewFlagSet(fullname, flag.ContinueOnError)
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	if hasTimeout && fs.Lookup(timeoutFlagName) == nil {
+		fs.Duration(timeoutFlagName, cmd.Timeout, timeoutUsage)
+	}
+	if cmd.Batch {
+		fs.String(batchFlagName, "", batchUsage)
+		fs.Bool(keepGoingFlagName, false, keepGoingUsage)
+	}
+	return flagDefs(fs)
+}
+
+// HelpError is the result of a request of help: the "-h", "-help" and "--help"
+// arguments, and the help command. It is not a failure: ExitCode returns ExitOK
+// and RunMain does not print it. errors.Is(err, flag.ErrHelp) reports whether
+// err is a request of help.
+type HelpError struct {
+	Path string // full name of the command (or topic) whose help was requested
+}
+
+func (e *HelpError) Error() string { return e.Path + ": " + flag.ErrHelp.Error() }
+
+// Unwrap returns flag.ErrHelp.
+func (e *HelpError) Unwrap() error { return flag.ErrHelp }
+
+// isHelpArg checks if the argument requests the help.
+func isHelpArg(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// showHelp prints, to the output of flag.CommandLine, the help of the command
+// cmd, with full name fullname and visible names in the parent command.
+// It returns a *HelpError, or the error of the help template.
+func (cmd *Command) showHelp(fullname string, names []string) error {
+	if err := printHelp(flag.CommandLine.Output(), fullname, names, cmd); err != nil {
+		return err
+	}
+	return &HelpError{Path: fullname}
+}
+
+// helpResult returns err as a *HelpError of the command with full name fullname,
+// if err is a request of help returned by the flag set of the command;
+// otherwise it returns err.
+func helpResult(fullname string, err error) error {
+	var he *HelpError
+	if err == nil || !errors.Is(err, flag.ErrHelp) || errors.As(err, &he) {
+		return err
+	}
+	return &HelpError{Path: fullname}
+}
@@ -0,0 +1,186 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPrintHelp(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *Command
+		want string
+	}{
+		{
+			name: "commands",
+			cmd: &Command{
+				SubCmd: map[string]*Command{
+					"tor,t":     {Short: "Checks if Tor network will be used"},
+					"get,g":     {Short: "Get the quotes of the specified isins"},
+					"sources,s": {Short: "Show available sources"},
+					"debug":     {Short: "Debug", Hidden: true},
+				},
+			},
+			want: `Usage:
+    app <command> [options]
+
+Available commands:
+    get      Get the quotes of the specified isins
+    sources  Show available sources
+    tor      Checks if Tor network will be used
+`,
+		},
+		{
+			name: "groups",
+			cmd: &Command{
+				Short: "Manage the quotes",
+				SubCmd: map[string]*Command{
+					"version":      {Short: "Print the version"},
+					"get,g":        {Short: "Get the quotes", Group: "Quotes commands"},
+					"sources,s":    {Short: "Show available sources", Group: "Quotes commands"},
+					"config":       {Short: "Show the configuration", Group: "Configuration commands"},
+					"help":         {},
+					"import,imp,i": {Short: "Import the quotes", Group: "Quotes commands"},
+				},
+			},
+			want: `Usage:
+    app <command> [options]
+
+Manage the quotes
+
+Available commands:
+    help
+    version  Print the version
+
+Configuration commands:
+    config   Show the configuration
+
+Quotes commands:
+    get      Get the quotes
+    import   Import the quotes
+    sources  Show available sources
+`,
+		},
+		{
+			name: "long description and examples",
+			cmd: &Command{
+				Short: "Checks if Tor network will be used",
+				Long: `Checks if Tor network will be used to get the quote.
+
+To use the Tor network the proxy must be defined.
+`,
+				Examples: []string{
+					"app --proxy socks5://127.0.0.1:9050",
+					"app --config config.yaml",
+				},
+			},
+			want: `Usage:
+    app [options]
+
+Checks if Tor network will be used to get the quote.
+
+To use the Tor network the proxy must be defined.
+
+Examples:
+    app --proxy socks5://127.0.0.1:9050
+    app --config config.yaml
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := PrintHelp(&buf, "app", tt.cmd); err != nil {
+				t.Fatalf("PrintHelp() error = %v, want nil", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("PrintHelp(): got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_helpRequest(t *testing.T) {
+	newApp := func() *Command {
+		var workers int
+		quotes := &Command{
+			Short: "Get the quotes",
+			Flags: func(fs *flag.FlagSet) {
+				AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+			},
+		}
+		quotes.ParseExec = func(name string, arguments []string) error {
+			fs := flag.NewFlagSet(name, flag.ContinueOnError)
+			fs.Usage = HelpUsage(name, quotes)
+			quotes.Flags(fs)
+			return fs.Parse(arguments)
+		}
+		return &Command{
+			HelpCommand: true,
+			SubCmd: map[string]*Command{
+				"quotes,q": quotes,
+				"get,g": {
+					Short: "Get the quotes",
+					SubCmd: map[string]*Command{
+						"isins": {Short: "Get the quotes of the isins"},
+					},
+				},
+				"tor": {
+					Short: "Checks the Tor network",
+					ParseExec: func(name string, arguments []string) error {
+						fs := flag.NewFlagSet(name, flag.ContinueOnError)
+						fs.Usage = func() { fmt.Fprintf(flag.CommandLine.Output(), "usage: %s", name) }
+						return fs.Parse(arguments)
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantOut  string
+	}{
+		{"root -h", []string{"-h"}, "app", "app <command> [options]"},
+		{"root --help", []string{"--help"}, "app", "app <command> [options]"},
+		{"root help", []string{"help"}, "app", "app <command> [options]"},
+		{"get -h", []string{"g", "-h"}, "app get", "Usage:\n    app get <command> [options]\n\nAliases: g\n"},
+		{"get -help", []string{"get", "-help"}, "app get", "Usage:\n    app get <command> [options]\n\nAliases: g\n"},
+		{"help -h", []string{"help", "-h"}, "app help", "Usage:\n    app help [options] [command...|topic]\n\nShow the help of a command or topic\n\nArguments:\n    [command...|topic]  path of sub-command names, or help topic\n"},
+		{"help get", []string{"help", "g"}, "app get", "Usage:\n    app get <command> [options]\n\nAliases: g\n"},
+		{"tor -h", []string{"tor", "-h"}, "app tor", "usage: app tor"},
+		{"quotes -h", []string{"q", "-h"}, "app quotes", "Usage:\n    app quotes [options]\n\nAliases: q\n\nGet the quotes\n\nOptions:\n"},
+		{"help quotes", []string{"help", "quotes"}, "app quotes", "Usage:\n    app quotes [options]\n\nAliases: q\n\nGet the quotes\n\nOptions:\n"},
+		{"quotes -w 4 -h", []string{"quotes", "-w", "4", "-h"}, "app quotes", "Usage:\n    app quotes [options]\n\nGet the quotes\n\nOptions:\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			err := newApp().handleSubCmd("app", tt.args)
+			if !errors.Is(err, flag.ErrHelp) {
+				t.Fatalf("error: got %v, want flag.ErrHelp", err)
+			}
+			var he *HelpError
+			if !errors.As(err, &he) {
+				t.Fatalf("error: got %T, want *HelpError", err)
+			}
+			if he.Path != tt.wantPath {
+				t.Errorf("Path: got %q, want %q", he.Path, tt.wantPath)
+			}
+			if ExitCode(err) != ExitOK {
+				t.Errorf("ExitCode: got %d, want %d", ExitCode(err), ExitOK)
+			}
+			if got := out.String(); !strings.Contains(got, tt.wantOut) {
+				t.Errorf("output: got\n%s\nwant\n%s", got, tt.wantOut)
+			}
+		})
+	}
+}
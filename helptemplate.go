@@ -0,0 +1,315 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// DefaultHelpTemplate is the text/template of the help of a command,
+// executed with the HelpData of the command.
+//
+// Besides the functions predefined by text/template, the template can use:
+//
+//	wrap indent text           the text wrapped at the help width, with hanging indent
+//	join sep list              the strings of list joined by sep
+//	commands .Commands         the GNU-like list of the commands of a group
+//	topics .Topics             the GNU-like list of the help topics
+//	useraliases .UserAliases   the GNU-like list of the user aliases
+//	positionals .Positionals   the GNU-like list of the positional arguments
+//	options .Flags             the GNU-like list of the flags, as printed by PrintDefaults
+//	heading text               the text styled as a heading, if the colors are enabled
+//
+// The lists are styled too if the colors are enabled (see Color).
+const DefaultHelpTemplate = `{{heading "Usage:"}}
+    {{.Usage}}
+{{- with .Aliases}}
+
+{{heading "Aliases:"}} {{join ", " .}}
+{{- end}}
+{{- with .Description}}
+
+{{wrap 0 .}}
+{{- end}}
+{{- range .Groups}}
+
+{{heading (print .Heading ":")}}
+{{commands .Commands}}
+{{- end}}
+{{- with .UserAliases}}
+
+{{heading "User aliases:"}}
+{{useraliases .}}
+{{- end}}
+{{- with .Topics}}
+
+{{heading "Help topics:"}}
+{{topics .}}
+{{- end}}
+{{- with .Positionals}}
+
+{{heading "Arguments:"}}
+{{positionals .}}
+{{- end}}
+{{- with .Flags}}
+
+{{heading "Options:"}}
+{{options .}}
+{{- end}}
+{{- with .Examples}}
+
+{{heading "Examples:"}}
+{{- range .}}
+    {{.}}
+{{- end}}
+{{- end}}
+`
+
+// Positional describes a positional argument of a command.
+type Positional struct {
+	Name  string // name of the argument, as shown in the usage line (example: "<isin>...")
+	Usage string // description of the argument
+}
+
+// HelpData is the data model of the help template of a command.
+type HelpData struct {
+	Path        string         // full name of the command (example: "app get")
+	Aliases     []string       // visible aliases of the command in the parent command
+	Usage       string         // usage line (example: "app get [options] <isin>...")
+	Short       string         // one-line description of the command
+	Long        string         // long description of the command
+	Description string         // long description, or the short one if the long is not defined
+	Groups      []*HelpGroup   // visible sub-commands grouped by Group
+	UserAliases []*HelpAlias   // user aliases not shadowed by the sub-commands
+	Topics      []*HelpTopic   // help topics
+	Positionals []*Positional  // positional arguments
+	Flags       []*HelpFlag    // visible flags, each one with its aliases
+	Examples    []string       // usage examples
+	Width       int            // width of the help
+	Color       bool           // the help is styled with ANSI escape sequences
+	commands    []*HelpCommand // all the sub-commands, used to align the lists
+}
+
+// HelpGroup is a group of sub-commands listed under the same heading.
+type HelpGroup struct {
+	Heading  string         // heading of the group
+	Commands []*HelpCommand // sub-commands of the group, sorted by name
+}
+
+// HelpCommand describes a sub-command in the help of the parent command.
+type HelpCommand struct {
+	Name    string   // primary name of the sub-command
+	Aliases []string // visible aliases of the sub-command
+	Short   string   // one-line description of the sub-command
+}
+
+// HelpAlias describes a user alias.
+type HelpAlias struct {
+	Name      string // name of the alias
+	Expansion string // expansion of the alias
+}
+
+// HelpTopic describes a help topic.
+type HelpTopic struct {
+	Name    string   // primary name of the topic
+	Aliases []string // aliases of the topic
+	Short   string   // one-line description of the topic
+}
+
+// HelpFlag describes a flag with its aliases.
+type HelpFlag struct {
+	Name      string   // primary name of the flag
+	Aliases   []string // visible aliases of the flag
+	Type      string   // name of the type of the value; empty for boolean flags
+	Usage     string   // usage string of the flag
+	Default   string   // default value, or the empty string if it is the zero value
+	Env       string   // environment variable bound to the flag
+	Required  bool     // the flag is required
+	Negatable bool     // the "no-<name>" flags are defined
+	Enum      []string // accepted values
+	def       *flagDef
+}
+
+// newHelpData returns the help data of the command cmd, with full name fullname
+// and visible names in the parent command, for a help of the given width.
+func newHelpData(fullname string, names []string, cmd *Command, width int) (*HelpData, error) {
+	scs, err := cmd.subCommands(fullname)
+	if err != nil {
+		return nil, err
+	}
+	aliases := cmd.activeUserAliases(scs)
+	plugins := cmd.plugins(fullname, scs)
+	scs = visibleSubCommands(scs)
+
+	data := &HelpData{
+		Path:        fullname,
+		Usage:       fullname + " [options]",
+		Short:       cmd.Short,
+		Long:        cmd.Long,
+		Description: strings.TrimRight(cmd.description(), "\n"),
+		Positionals: cmd.Positionals,
+		Examples:    cmd.Examples,
+		Width:       width,
+	}
+	if len(names) > 1 {
+		data.Aliases = names[1:]
+	}
+	if len(scs) > 0 || len(plugins) > 0 {
+		data.Usage = fullname + " <command> [options]"
+	}
+	for _, p := range cmd.Positionals {
+		data.Usage += " " + p.Name
+	}
+
+	for _, g := range groupSubCommands(scs) {
+		hg := &HelpGroup{Heading: g.heading}
+		for _, sc := range g.commands {
+			vn := sc.visibleNames()
+			hc := &HelpCommand{Name: vn[0], Aliases: vn[1:], Short: sc.cmd.Short}
+			hg.Commands = append(hg.Commands, hc)
+			data.commands = append(data.commands, hc)
+		}
+		data.Groups = append(data.Groups, hg)
+	}
+
+	if len(plugins) > 0 {
+		hg := &HelpGroup{Heading: pluginGroup}
+		for _, name := range plugins {
+			hc := &HelpCommand{Name: name}
+			hg.Commands = append(hg.Commands, hc)
+			data.commands = append(data.commands, hc)
+		}
+		data.Groups = append(data.Groups, hg)
+	}
+
+	for _, a := range aliases {
+		data.UserAliases = append(data.UserAliases, &HelpAlias{Name: a.name, Expansion: a.expansion})
+	}
+
+	for _, t := range cmd.topics() {
+		data.Topics = append(data.Topics, &HelpTopic{Name: t.names[0], Aliases: t.names[1:], Short: t.topic.Short})
+	}
+
+	for _, d := range cmd.flagDefs(fullname) {
+		vn := d.visibleNames()
+		hf := &HelpFlag{
+			Name:      vn[0],
+			Aliases:   vn[1:],
+			Type:      d.typ,
+			Usage:     d.usage,
+			Default:   d.defaultValue(),
+			Env:       d.env(),
+			Negatable: d.negatable,
+			def:       d,
+		}
+		if d.info != nil {
+			hf.Required = d.info.required
+			hf.Enum = d.info.enum
+		}
+		data.Flags = append(data.Flags, hf)
+	}
+	return data, nil
+}
+
+// list returns the lines of a GNU-like list of names and descriptions,
+// without the trailing newline. The descriptions are aligned in a column
+// and wrapped at the help width.
+func (data *HelpData) list(names, descs []string) string {
+	nw := 0
+	for _, n := range names {
+		if len(n) > nw {
+			nw = len(n)
+		}
+	}
+	lines := make([]string, len(names))
+	for j, n := range names {
+		line := fmt.Sprintf("    %s%s  %s", data.styler().flagName(n), strings.Repeat(" ", nw-len(n)), wrapText(descs[j], nw+6, data.Width))
+		lines[j] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// styler returns the styler of the help.
+func (data *HelpData) styler() styler {
+	return styler(data.Color)
+}
+
+// funcs returns the functions of the help template.
+func (data *HelpData) funcs() template.FuncMap {
+	return template.FuncMap{
+		"wrap": func(indent int, s string) string {
+			return wrapText(s, indent, data.Width)
+		},
+		"heading": func(s string) string {
+			return data.styler().heading(s)
+		},
+		"join": func(sep string, a []string) string {
+			return strings.Join(a, sep)
+		},
+		"commands": func(cmds []*HelpCommand) string {
+			// the names are aligned with the ones of the other groups
+			names, descs := []string{}, []string{}
+			for _, c := range data.commands {
+				names = append(names, c.Name)
+				descs = append(descs, c.Short)
+			}
+			lines := strings.Split(data.list(names, descs), "\n")
+			res := []string{}
+			for j, c := range data.commands {
+				for _, gc := range cmds {
+					if gc == c {
+						res = append(res, lines[j])
+					}
+				}
+			}
+			return strings.Join(res, "\n")
+		},
+		"useraliases": func(aliases []*HelpAlias) string {
+			names, descs := []string{}, []string{}
+			for _, a := range aliases {
+				names = append(names, a.Name)
+				descs = append(descs, a.Expansion)
+			}
+			return data.list(names, descs)
+		},
+		"topics": func(topics []*HelpTopic) string {
+			names, descs := []string{}, []string{}
+			for _, t := range topics {
+				names = append(names, t.Name)
+				descs = append(descs, t.Short)
+			}
+			return data.list(names, descs)
+		},
+		"positionals": func(ps []*Positional) string {
+			names, descs := []string{}, []string{}
+			for _, p := range ps {
+				names = append(names, p.Name)
+				descs = append(descs, p.Usage)
+			}
+			return data.list(names, descs)
+		},
+		"options": func(flags []*HelpFlag) string {
+			defs := []*flagDef{}
+			for _, f := range flags {
+				defs = append(defs, f.def)
+			}
+			var buf strings.Builder
+			printDefaults(&buf, defs, data.Width, data.styler())
+			return strings.TrimRight(buf.String(), "\n")
+		},
+	}
+}
+
+// execute executes the help template text with the data, writing the help to w.
+// If text is empty, DefaultHelpTemplate is used.
+func (data *HelpData) execute(w io.Writer, text string) error {
+	if text == "" {
+		text = DefaultHelpTemplate
+	}
+	tmpl, err := template.New("help").Funcs(data.funcs()).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
@@ -0,0 +1,91 @@
+package flagx
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestPrintHelp_Template(t *testing.T) {
+	defer func(w int) { HelpWidth = w }(HelpWidth)
+	HelpWidth = 80
+
+	var (
+		workers int
+		config  string
+	)
+	flags := func(fs *flag.FlagSet) {
+		AliasedStringVar(fs, &config, "config,c", "", "config file", Required(), Env("APP_CONFIG"))
+		AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+	}
+
+	tests := []struct {
+		name string
+		cmd  *Command
+		want string
+	}{
+		{
+			name: "positionals",
+			cmd: &Command{
+				Short: "Get the quotes",
+				Positionals: []*Positional{
+					{Name: "<isin>...", Usage: "isins to get the quotes"},
+				},
+				Flags: flags,
+			},
+			want: `Usage:
+    app [options] <isin>...
+
+Get the quotes
+
+Arguments:
+    <isin>...  isins to get the quotes
+
+Options:
+    -c, --config  string  config file [$APP_CONFIG]
+    -w, --workers int     number of workers (default 1)
+`,
+		},
+		{
+			name: "custom template",
+			cmd: &Command{
+				Short: "Get the quotes",
+				SubCmd: map[string]*Command{
+					"isins,i": {Short: "Get the isins"},
+				},
+				Flags: flags,
+				HelpTemplate: `{{.Path}}: {{.Short}}
+{{range .Groups}}{{range .Commands}}* {{.Name}} ({{join ", " .Aliases}})
+{{end}}{{end}}{{range .Flags}}--{{.Name}}{{if .Required}} (required){{end}}{{with .Env}} ${{.}}{{end}}
+{{end}}width={{.Width}}
+`,
+			},
+			want: `app: Get the quotes
+* isins (i)
+--config (required) $APP_CONFIG
+--workers
+width=80
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := PrintHelp(&buf, "app", tt.cmd); err != nil {
+				t.Fatalf("PrintHelp() error = %v, want nil", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("PrintHelp(): got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintHelp_InvalidTemplate(t *testing.T) {
+	cmd := &Command{HelpTemplate: "{{.Unknown"}
+	var buf strings.Builder
+	if err := PrintHelp(&buf, "app", cmd); err == nil {
+		t.Errorf("PrintHelp() error = nil, want not nil")
+	}
+}
@@ -0,0 +1,76 @@
+package flagx
+
+// HookFunc is the signature of the functions called before
+// the execution of a command (see Command.Before).
+type HookFunc func(fullname string, arguments []string) error
+
+// AfterHookFunc is the signature of the functions called after
+// the execution of a command (see Command.After).
+// err is the error of the command; the returned error replaces it.
+type AfterHookFunc func(fullname string, arguments []string, err error) error
+
+// Middleware wraps the execution of a command: it returns a ParseExecFunc
+// that usually calls next. For example, a middleware that logs the commands:
+//
+//	func logging(next flagx.ParseExecFunc) flagx.ParseExecFunc {
+//		return func(fullname string, arguments []string) error {
+//			log.Printf("running %s", fullname)
+//			return next(fullname, arguments)
+//		}
+//	}
+type Middleware func(next ParseExecFunc) ParseExecFunc
+
+// chain returns the execution function exec of the command, with the given
+// ancestors from the root, wrapped by the hooks and the middlewares.
+func (cmd *Command) chain(parents []*Command, exec ParseExecFunc) ParseExecFunc {
+	path := make([]*Command, 0, len(parents)+1)
+	path = append(append(path, parents...), cmd)
+
+	run := func(fullname string, arguments []string) error {
+		err := cmd.runBefore(path, fullname, arguments)
+		if err == nil {
+			err = exec(fullname, arguments)
+		}
+		return cmd.runAfter(path, fullname, arguments, err)
+	}
+
+	for j := len(path) - 1; j >= 0; j-- {
+		mws := path[j].Middleware
+		for k := len(mws) - 1; k >= 0; k-- {
+			run = mws[k](run)
+		}
+	}
+	return run
+}
+
+// runBefore calls the persistent before hooks of the path from the root
+// to the command, then the Before hook of the command.
+// It stops at the first error.
+func (cmd *Command) runBefore(path []*Command, fullname string, arguments []string) error {
+	for _, c := range path {
+		if c.PersistentBefore != nil {
+			if err := c.PersistentBefore(fullname, arguments); err != nil {
+				return err
+			}
+		}
+	}
+	if cmd.Before != nil {
+		return cmd.Before(fullname, arguments)
+	}
+	return nil
+}
+
+// runAfter calls the After hook of the command, then the persistent
+// after hooks of the path from the command to the root, passing each one
+// the error returned by the previous.
+func (cmd *Command) runAfter(path []*Command, fullname string, arguments []string, err error) error {
+	if cmd.After != nil {
+		err = cmd.After(fullname, arguments, err)
+	}
+	for j := len(path) - 1; j >= 0; j-- {
+		if h := path[j].PersistentAfter; h != nil {
+			err = h(fullname, arguments, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+package flagx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCommand_hooks(t *testing.T) {
+	errExec := errors.New("exec error")
+	errBefore := errors.New("before error")
+
+	var calls []string
+	record := func(s string) { calls = append(calls, s) }
+	before := func(s string, err error) HookFunc {
+		return func(fullname string, arguments []string) error {
+			record(s + " " + fullname)
+			return err
+		}
+	}
+	after := func(s string) AfterHookFunc {
+		return func(fullname string, arguments []string, err error) error {
+			record(s + " " + fullname)
+			return err
+		}
+	}
+	middleware := func(s string) Middleware {
+		return func(next ParseExecFunc) ParseExecFunc {
+			return func(fullname string, arguments []string) error {
+				record(s + " in")
+				err := next(fullname, arguments)
+				record(s + " out")
+				return err
+			}
+		}
+	}
+
+	newApp := func(beforeErr, execErr error) *Command {
+		return &Command{
+			PersistentBefore: before("root pre", nil),
+			PersistentAfter:  after("root post"),
+			Before:           before("root before", nil),
+			After:            after("root after"),
+			Middleware:       []Middleware{middleware("mw1"), middleware("mw2")},
+			SubCmd: map[string]*Command{
+				"get": {
+					PersistentBefore: before("get pre", nil),
+					PersistentAfter:  after("get post"),
+					Before:           before("get before", beforeErr),
+					After:            after("get after"),
+					Middleware:       []Middleware{middleware("mw3")},
+					ParseExec: func(fullname string, arguments []string) error {
+						record("exec " + fullname)
+						return execErr
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		beforeErr error
+		execErr   error
+		wantErr   error
+		want      []string
+	}{
+		{
+			name: "success",
+			want: []string{
+				"mw1 in", "mw2 in", "mw3 in",
+				"root pre app get", "get pre app get", "get before app get",
+				"exec app get",
+				"get after app get", "get post app get", "root post app get",
+				"mw3 out", "mw2 out", "mw1 out",
+			},
+		},
+		{
+			name:    "exec error",
+			execErr: errExec,
+			wantErr: errExec,
+			want: []string{
+				"mw1 in", "mw2 in", "mw3 in",
+				"root pre app get", "get pre app get", "get before app get",
+				"exec app get",
+				"get after app get", "get post app get", "root post app get",
+				"mw3 out", "mw2 out", "mw1 out",
+			},
+		},
+		{
+			name:      "before error",
+			beforeErr: errBefore,
+			wantErr:   errBefore,
+			want: []string{
+				"mw1 in", "mw2 in", "mw3 in",
+				"root pre app get", "get pre app get", "get before app get",
+				"get after app get", "get post app get", "root post app get",
+				"mw3 out", "mw2 out", "mw1 out",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			err := newApp(tt.beforeErr, tt.execErr).handleSubCmd("app", []string{"get"})
+			if err != tt.wantErr {
+				t.Errorf("error: got %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.want) {
+				t.Errorf("calls:\ngot  %q\nwant %q", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_afterHookReplacesError(t *testing.T) {
+	errExec := errors.New("exec error")
+	var got error
+	app := &Command{
+		PersistentAfter: func(fullname string, arguments []string, err error) error {
+			got = err
+			return nil
+		},
+		SubCmd: map[string]*Command{
+			"get": {
+				ParseExec: func(string, []string) error { return errExec },
+			},
+		},
+	}
+	if err := app.handleSubCmd("app", []string{"get"}); err != nil {
+		t.Errorf("error: got %v, want nil", err)
+	}
+	if got != errExec {
+		t.Errorf("after hook error: got %v, want %v", got, errExec)
+	}
+}
@@ -0,0 +1,264 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManOptions are the options of the man pages generated by
+// WriteManPage, WriteManPages and GenManPages.
+type ManOptions struct {
+	Section string // section of the manual; "1" if empty
+	Date    string // date of the last change, shown in the footer
+	Source  string // source of the command, like "quotes 1.2.0"
+	Manual  string // title of the manual, like "User Commands"
+}
+
+// section returns the section of the man pages.
+func (o *ManOptions) section() string {
+	if o == nil || o.Section == "" {
+		return "1"
+	}
+	return o.Section
+}
+
+// header returns the .TH header of the page with the given name.
+func (o *ManOptions) header(name string) string {
+	var date, source, manual string
+	if o != nil {
+		date, source, manual = o.Date, o.Source, o.Manual
+	}
+	return fmt.Sprintf(".TH \"%s\" \"%s\" \"%s\" \"%s\" \"%s\"\n",
+		strings.ToUpper(roffEscape(name)), roffEscape(o.section()), roffEscape(date), roffEscape(source), roffEscape(manual))
+}
+
+// roffEscape escapes the text s for roff.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for j, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[j] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roffParagraphs returns the text as roff paragraphs: the empty lines are
+// replaced by the .PP macro.
+func roffParagraphs(s string) string {
+	s = roffEscape(strings.TrimSpace(s))
+	return strings.ReplaceAll(s, "\n\n", "\n.PP\n")
+}
+
+// roffFlagNames returns the names of the flag for the OPTIONS section.
+func roffFlagNames(d *flagDef) string {
+	names := []string{}
+	for _, n := range d.shortNames() {
+		names = append(names, `\fB\-`+roffEscape(n)+`\fR`)
+	}
+	for _, n := range d.longNames() {
+		if d.negatable {
+			names = append(names, `\fB\-\-\fR[\fBno\-\fR]\fB`+roffEscape(n)+`\fR`)
+		} else {
+			names = append(names, `\fB\-\-`+roffEscape(n)+`\fR`)
+		}
+	}
+	s := strings.Join(names, ", ")
+	if d.typ != "" {
+		s += ` \fI` + roffEscape(d.typ) + `\fR`
+	}
+	return s
+}
+
+// writeManOptions writes the OPTIONS section of the flags,
+// with the given section macro.
+func writeManOptions(w io.Writer, macro string, defs []*flagDef) {
+	if len(defs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s OPTIONS\n", macro)
+	for _, d := range defs {
+		usage := d.usage
+		if dv := d.defaultValue(); dv != "" {
+			usage += " (default " + dv + ")"
+		}
+		fmt.Fprintf(w, ".TP\n%s\n%s\n", roffFlagNames(d), roffEscape(usage))
+	}
+}
+
+// writeManEnvironment writes the ENVIRONMENT section of the flags bound to
+// an environment variable. It does nothing if there are no such flags.
+func writeManEnvironment(w io.Writer, defs []*flagDef) {
+	title := false
+	for _, d := range defs {
+		if d.env() == "" {
+			continue
+		}
+		if !title {
+			fmt.Fprintf(w, ".SH ENVIRONMENT\n")
+			title = true
+		}
+		fmt.Fprintf(w, ".TP\n.B %s\n%s (see \\fB%s\\fR)\n", roffEscape(d.env()), roffEscape(d.usage), roffEscape(flagArg(d.names[0])))
+	}
+}
+
+// writeManCommands writes the COMMANDS section of the children of the node.
+func writeManCommands(w io.Writer, macro string, n *commandNode) {
+	if len(n.children) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s COMMANDS\n", macro)
+	for _, c := range n.children {
+		fmt.Fprintf(w, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(strings.Join(c.names, ", ")), roffEscape(c.cmd.Short))
+	}
+}
+
+// writeManSynopsis writes the synopsis of the node.
+func writeManSynopsis(w io.Writer, n *commandNode) {
+	fmt.Fprintf(w, ".B %s\n", roffEscape(n.fullname))
+	if len(n.children) > 0 {
+		fmt.Fprintf(w, "\\fIcommand\\fR\n")
+	}
+	fmt.Fprintf(w, "[\\fIoptions\\fR]\n")
+}
+
+// writeManExamples writes the EXAMPLES section of the command.
+func writeManExamples(w io.Writer, macro string, cmd *Command) {
+	if len(cmd.Examples) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s EXAMPLES\n", macro)
+	for _, ex := range cmd.Examples {
+		fmt.Fprintf(w, ".PP\n.nf\n%s\n.fi\n", roffEscape(ex))
+	}
+}
+
+// writeManHead writes the header and the NAME, SYNOPSIS, DESCRIPTION,
+// COMMANDS and OPTIONS sections of the man page of the node.
+func writeManHead(w io.Writer, n *commandNode, defs []*flagDef, opts *ManOptions) {
+	fmt.Fprint(w, opts.header(n.pageName()))
+
+	fmt.Fprintf(w, ".SH NAME\n%s", roffEscape(n.pageName()))
+	if n.cmd.Short != "" {
+		fmt.Fprintf(w, ` \- %s`, roffEscape(n.cmd.Short))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, ".SH SYNOPSIS\n")
+	writeManSynopsis(w, n)
+
+	if desc := n.cmd.description(); desc != "" {
+		fmt.Fprintf(w, ".SH DESCRIPTION\n%s\n", roffParagraphs(desc))
+	}
+	if len(n.names) > 1 {
+		fmt.Fprintf(w, ".PP\nAliases: %s\n", roffEscape(strings.Join(n.names[1:], ", ")))
+	}
+
+	writeManCommands(w, ".SH", n)
+	writeManOptions(w, ".SH", defs)
+}
+
+// writeManSeeAlso writes the SEE ALSO section of the node, linking
+// the parent, the sibling and the children commands.
+func writeManSeeAlso(w io.Writer, n *commandNode, opts *ManOptions) {
+	related := []*commandNode{}
+	if n.parent != nil {
+		related = append(related, n.parent)
+	}
+	related = append(related, n.siblings()...)
+	related = append(related, n.children...)
+	if len(related) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, ".SH SEE ALSO\n")
+	for j, r := range related {
+		sep := ","
+		if j == len(related)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(w, ".BR %s (%s)%s\n", roffEscape(r.pageName()), opts.section(), sep)
+	}
+}
+
+// writeManPage writes the man page of the node.
+func writeManPage(w io.Writer, n *commandNode, opts *ManOptions) {
+	defs := n.cmd.flagDefs(n.fullname)
+
+	writeManHead(w, n, defs, opts)
+	writeManEnvironment(w, defs)
+	writeManExamples(w, ".SH", n.cmd)
+	writeManSeeAlso(w, n, opts)
+}
+
+// WriteManPage writes to w the man page of the command cmd with full name fullname.
+// The page includes the NAME, SYNOPSIS, DESCRIPTION, COMMANDS, OPTIONS,
+// ENVIRONMENT and EXAMPLES sections, as available.
+func WriteManPage(w io.Writer, fullname string, cmd *Command, opts *ManOptions) error {
+	n, err := commandTree(fullname, cmd)
+	if err != nil {
+		return err
+	}
+	writeManPage(w, n, opts)
+	return nil
+}
+
+// WriteManPages writes to w a single man page of the root command app with name
+// appname, including the description and the options of all its visible sub-commands.
+func WriteManPages(w io.Writer, appname string, app *Command, opts *ManOptions) error {
+	root, err := commandTree(appname, app)
+	if err != nil {
+		return err
+	}
+
+	envDefs := root.cmd.flagDefs(root.fullname)
+	writeManHead(w, root, envDefs, opts)
+
+	for _, c := range root.children {
+		c.walk(func(n *commandNode) error {
+			defs := n.cmd.flagDefs(n.fullname)
+			envDefs = append(envDefs, defs...)
+
+			fmt.Fprintf(w, ".SH \"%s\"\n", strings.ToUpper(roffEscape(n.fullname)))
+			if len(n.names) > 1 {
+				fmt.Fprintf(w, "Aliases: %s\n.PP\n", roffEscape(strings.Join(n.names[1:], ", ")))
+			}
+			writeManSynopsis(w, n)
+			if desc := n.cmd.description(); desc != "" {
+				fmt.Fprintf(w, ".PP\n%s\n", roffParagraphs(desc))
+			}
+			writeManCommands(w, ".SS", n)
+			writeManOptions(w, ".SS", defs)
+			writeManExamples(w, ".SS", n.cmd)
+			return nil
+		})
+	}
+	writeManEnvironment(w, envDefs)
+	writeManExamples(w, ".SH", root.cmd)
+	return nil
+}
+
+// GenManPages writes in the directory dir a man page for each visible command
+// of the root command app with name appname. The name of each file is the
+// full name of the command joined by '-', followed by the section
+// (example: "app-get.1").
+func GenManPages(dir, appname string, app *Command, opts *ManOptions) error {
+	root, err := commandTree(appname, app)
+	if err != nil {
+		return err
+	}
+
+	return root.walk(func(n *commandNode) error {
+		f, err := os.Create(filepath.Join(dir, n.pageName()+"."+opts.section()))
+		if err != nil {
+			return err
+		}
+		writeManPage(f, n, opts)
+		return f.Close()
+	})
+}
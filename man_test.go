@@ -0,0 +1,125 @@
+package flagx
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_roffEscape(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"text", "text"},
+		{"--dry-run", `\-\-dry\-run`},
+		{`C:\path`, `C:\epath`},
+		{".dot\n'quote", "\\&.dot\n\\&'quote"},
+	}
+	for _, tt := range tests {
+		if got := roffEscape(tt.s); got != tt.want {
+			t.Errorf("roffEscape(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGenManPages(t *testing.T) {
+	dir := t.TempDir()
+	opts := &ManOptions{Date: "2021-10-01", Source: "quotes 1.0", Manual: "User Commands"}
+
+	if err := GenManPages(dir, "app", testQuotesApp(), opts); err != nil {
+		t.Fatalf("GenManPages() error = %v, want nil", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	sort.Strings(got)
+	if want := "app-get.1 app-sources.1 app.1"; strings.Join(got, " ") != want {
+		t.Errorf("GenManPages() files = %v, want %v", got, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app-get.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `.TH "APP\-GET" "1" "2021\-10\-01" "quotes 1.0" "User Commands"
+.SH NAME
+app\-get \- Get the quotes of the specified isins
+.SH SYNOPSIS
+.B app get
+[\fIoptions\fR]
+.SH DESCRIPTION
+Get the quotes of the specified isins.
+.PP
+The quotes are saved in the database.
+.PP
+Aliases: g
+.SH OPTIONS
+.TP
+\fB\-d\fR, \fB\-\-database\fR \fIstring\fR
+sqlite3 database
+.TP
+\fB\-n\fR, \fB\-\-\fR[\fBno\-\fR]\fBdry\-run\fR
+perform a trial run with no request/updates made
+.TP
+\fB\-i\fR, \fB\-\-isins\fR \fIstrings\fR
+list of isins to get the quotes
+.TP
+\fB\-p\fR, \fB\-\-proxy\fR \fIstring\fR
+default proxy
+.TP
+\fB\-w\fR, \fB\-\-workers\fR \fIint\fR
+number of workers (default 1)
+.SH ENVIRONMENT
+.TP
+.B QUOTES_PROXY
+default proxy (see \fB\-\-proxy\fR)
+.SH EXAMPLES
+.PP
+.nf
+app get \-i isin1,isin2 \-w 4
+.fi
+.SH SEE ALSO
+.BR app (1),
+.BR app\-sources (1)
+`
+	if string(data) != want {
+		t.Errorf("app-get.1: got\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestWriteManPages(t *testing.T) {
+	var buf strings.Builder
+	if err := WriteManPages(&buf, "app", testQuotesApp(), nil); err != nil {
+		t.Fatalf("WriteManPages() error = %v, want nil", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		`.TH "APP" "1" "" "" ""`,
+		".SH COMMANDS\n.TP\n\\fBget, g\\fR\n",
+		".SH \"APP GET\"\n",
+		".SS OPTIONS\n",
+		".SH \"APP SOURCES\"\n",
+		".SH ENVIRONMENT\n.TP\n.B QUOTES_CONFIG\n",
+		".B QUOTES_PROXY\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteManPages(): got\n%s\nwant substring %q", got, want)
+		}
+	}
+	if strings.Contains(got, "tor") {
+		t.Errorf("WriteManPages(): got deprecated command tor")
+	}
+	if strings.Count(got, ".SH ENVIRONMENT") != 1 {
+		t.Errorf("WriteManPages(): want one ENVIRONMENT section")
+	}
+}
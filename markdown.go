@@ -0,0 +1,211 @@
+package flagx
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// docFlagNames returns the visible names of the flag, as passed in the command line.
+func docFlagNames(d *flagDef) []string {
+	names := []string{}
+	for _, n := range d.shortNames() {
+		names = append(names, "-"+n)
+	}
+	for _, n := range d.longNames() {
+		names = append(names, "--"+n)
+		if d.negatable {
+			names = append(names, "--"+negatedName(n))
+		}
+	}
+	return names
+}
+
+// docFlagUsage returns the usage of the flag, followed by the bound
+// environment variable, if any.
+func docFlagUsage(d *flagDef) string {
+	usage := d.usage
+	if env := d.env(); env != "" {
+		usage += " (environment: " + env + ")"
+	}
+	return usage
+}
+
+// docUsage returns the usage line of the node.
+func docUsage(n *commandNode) string {
+	if len(n.children) > 0 {
+		return n.fullname + " <command> [options]"
+	}
+	return n.fullname + " [options]"
+}
+
+// mdCell escapes the text s for a cell of a Markdown table.
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
+
+// mdCode returns the strings as Markdown code spans, joined by ", ".
+func mdCode(ss []string) string {
+	res := make([]string, len(ss))
+	for j, s := range ss {
+		res[j] = "`" + s + "`"
+	}
+	return strings.Join(res, ", ")
+}
+
+// writeMarkdownPage writes the Markdown page of the node.
+// The links to the other pages use the ".md" extension.
+func writeMarkdownPage(w io.Writer, n *commandNode) {
+	fmt.Fprintf(w, "# %s\n", n.fullname)
+	if desc := n.cmd.description(); desc != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(desc))
+	}
+
+	fmt.Fprintf(w, "\n## Usage\n\n```text\n%s\n```\n", docUsage(n))
+	if len(n.names) > 1 {
+		fmt.Fprintf(w, "\nAliases: %s\n", mdCode(n.names[1:]))
+	}
+
+	if len(n.children) > 0 {
+		fmt.Fprintf(w, "\n## Commands\n\n| Command | Aliases | Description |\n| --- | --- | --- |\n")
+		for _, c := range n.children {
+			fmt.Fprintf(w, "| [%s](%s.md) | %s | %s |\n", c.names[0], c.pageName(), mdCode(c.names[1:]), mdCell(c.cmd.Short))
+		}
+	}
+
+	if defs := n.cmd.flagDefs(n.fullname); len(defs) > 0 {
+		fmt.Fprintf(w, "\n## Options\n\n| Option | Type | Default | Description |\n| --- | --- | --- | --- |\n")
+		for _, d := range defs {
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", mdCode(docFlagNames(d)), d.typ, mdCell(d.defaultValue()), mdCell(docFlagUsage(d)))
+		}
+	}
+
+	if len(n.cmd.Examples) > 0 {
+		fmt.Fprintf(w, "\n## Examples\n\n```text\n%s\n```\n", strings.Join(n.cmd.Examples, "\n"))
+	}
+
+	if n.parent != nil || len(n.children) > 0 {
+		fmt.Fprintf(w, "\n## See also\n\n")
+		if p := n.parent; p != nil {
+			fmt.Fprintf(w, "- [%s](%s.md) - %s\n", p.fullname, p.pageName(), p.cmd.Short)
+		}
+		for _, c := range n.children {
+			fmt.Fprintf(w, "- [%s](%s.md) - %s\n", c.fullname, c.pageName(), c.cmd.Short)
+		}
+	}
+}
+
+// writeHTMLPage writes the HTML page of the node.
+// The links to the other pages use the ".html" extension.
+func writeHTMLPage(w io.Writer, n *commandNode) {
+	esc := html.EscapeString
+
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", esc(n.fullname))
+	fmt.Fprintf(w, "<h1>%s</h1>\n", esc(n.fullname))
+	if desc := n.cmd.description(); desc != "" {
+		for _, p := range strings.Split(strings.TrimSpace(desc), "\n\n") {
+			fmt.Fprintf(w, "<p>%s</p>\n", esc(p))
+		}
+	}
+
+	fmt.Fprintf(w, "<h2>Usage</h2>\n<pre>%s</pre>\n", esc(docUsage(n)))
+	if len(n.names) > 1 {
+		fmt.Fprintf(w, "<p>Aliases: <code>%s</code></p>\n", esc(strings.Join(n.names[1:], ", ")))
+	}
+
+	if len(n.children) > 0 {
+		fmt.Fprintf(w, "<h2>Commands</h2>\n<table>\n<tr><th>Command</th><th>Aliases</th><th>Description</th></tr>\n")
+		for _, c := range n.children {
+			fmt.Fprintf(w, "<tr><td><a href=\"%s.html\">%s</a></td><td><code>%s</code></td><td>%s</td></tr>\n",
+				esc(c.pageName()), esc(c.names[0]), esc(strings.Join(c.names[1:], ", ")), esc(c.cmd.Short))
+		}
+		fmt.Fprintf(w, "</table>\n")
+	}
+
+	if defs := n.cmd.flagDefs(n.fullname); len(defs) > 0 {
+		fmt.Fprintf(w, "<h2>Options</h2>\n<table>\n<tr><th>Option</th><th>Type</th><th>Default</th><th>Description</th></tr>\n")
+		for _, d := range defs {
+			fmt.Fprintf(w, "<tr><td><code>%s</code></td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				esc(strings.Join(docFlagNames(d), ", ")), esc(d.typ), esc(d.defaultValue()), esc(docFlagUsage(d)))
+		}
+		fmt.Fprintf(w, "</table>\n")
+	}
+
+	if len(n.cmd.Examples) > 0 {
+		fmt.Fprintf(w, "<h2>Examples</h2>\n<pre>%s</pre>\n", esc(strings.Join(n.cmd.Examples, "\n")))
+	}
+
+	if n.parent != nil || len(n.children) > 0 {
+		fmt.Fprintf(w, "<h2>See also</h2>\n<ul>\n")
+		links := append([]*commandNode{}, n.children...)
+		if n.parent != nil {
+			links = append([]*commandNode{n.parent}, links...)
+		}
+		for _, l := range links {
+			fmt.Fprintf(w, "<li><a href=\"%s.html\">%s</a> - %s</li>\n", esc(l.pageName()), esc(l.fullname), esc(l.cmd.Short))
+		}
+		fmt.Fprintf(w, "</ul>\n")
+	}
+
+	fmt.Fprintf(w, "</body>\n</html>\n")
+}
+
+// WriteMarkdown writes to w the Markdown page of the command cmd with full name fullname.
+func WriteMarkdown(w io.Writer, fullname string, cmd *Command) error {
+	n, err := commandTree(fullname, cmd)
+	if err != nil {
+		return err
+	}
+	writeMarkdownPage(w, n)
+	return nil
+}
+
+// genDocs returns the pages of the visible commands of the root command app
+// with name appname, written by writePage. The key of the map is the file name
+// of the page: the full name of the command joined by '-', followed by ext.
+func genDocs(appname string, app *Command, ext string, writePage func(io.Writer, *commandNode)) (map[string]string, error) {
+	root, err := commandTree(appname, app)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := map[string]string{}
+	root.walk(func(n *commandNode) error {
+		var buf strings.Builder
+		writePage(&buf, n)
+		docs[n.pageName()+ext] = buf.String()
+		return nil
+	})
+	return docs, nil
+}
+
+// MarkdownDocs returns the Markdown pages of the visible commands of the root
+// command app with name appname. The key of the map is the file name of the page
+// (example: "app-get.md"), and the value is its content. The pages are linked
+// to the parent and to the children commands.
+//
+// It can be used in a test to check that the documentation is up to date.
+func MarkdownDocs(appname string, app *Command) (map[string]string, error) {
+	return genDocs(appname, app, ".md", writeMarkdownPage)
+}
+
+// HTMLDocs returns the HTML pages of the visible commands of the root
+// command app with name appname. The key of the map is the file name of the page
+// (example: "app-get.html"), and the value is its content.
+func HTMLDocs(appname string, app *Command) (map[string]string, error) {
+	return genDocs(appname, app, ".html", writeHTMLPage)
+}
+
+// WriteDocs writes the docs, as returned by MarkdownDocs or HTMLDocs, in the directory dir.
+func WriteDocs(dir string, docs map[string]string) error {
+	for name, content := range docs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
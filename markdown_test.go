@@ -0,0 +1,113 @@
+package flagx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownDocs(t *testing.T) {
+	docs, err := MarkdownDocs("app", testQuotesApp())
+	if err != nil {
+		t.Fatalf("MarkdownDocs() error = %v, want nil", err)
+	}
+
+	names := []string{}
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if want := []string{"app-get.md", "app-sources.md", "app.md"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("MarkdownDocs() pages = %v, want %v", names, want)
+	}
+
+	want := "# app get\n" +
+		"\n" +
+		"Get the quotes of the specified isins.\n" +
+		"\n" +
+		"The quotes are saved in the database.\n" +
+		"\n" +
+		"## Usage\n" +
+		"\n" +
+		"```text\n" +
+		"app get [options]\n" +
+		"```\n" +
+		"\n" +
+		"Aliases: `g`\n" +
+		"\n" +
+		"## Options\n" +
+		"\n" +
+		"| Option | Type | Default | Description |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| `-d`, `--database` | string |  | sqlite3 database |\n" +
+		"| `-n`, `--dry-run`, `--no-dry-run` |  |  | perform a trial run with no request/updates made |\n" +
+		"| `-i`, `--isins` | strings |  | list of isins to get the quotes |\n" +
+		"| `-p`, `--proxy` | string |  | default proxy (environment: QUOTES_PROXY) |\n" +
+		"| `-w`, `--workers` | int | 1 | number of workers |\n" +
+		"\n" +
+		"## Examples\n" +
+		"\n" +
+		"```text\n" +
+		"app get -i isin1,isin2 -w 4\n" +
+		"```\n" +
+		"\n" +
+		"## See also\n" +
+		"\n" +
+		"- [app](app.md) - Manage the quotes of the isins\n"
+	if got := docs["app-get.md"]; got != want {
+		t.Errorf("app-get.md: got\n%s\nwant\n%s", got, want)
+	}
+
+	for _, want := range []string{
+		"| [get](app-get.md) | `g` | Get the quotes of the specified isins |\n",
+		"- [app sources](app-sources.md) - Show available sources\n",
+	} {
+		if !strings.Contains(docs["app.md"], want) {
+			t.Errorf("app.md: got\n%s\nwant substring %q", docs["app.md"], want)
+		}
+	}
+}
+
+func TestHTMLDocs(t *testing.T) {
+	docs, err := HTMLDocs("app", testQuotesApp())
+	if err != nil {
+		t.Fatalf("HTMLDocs() error = %v, want nil", err)
+	}
+	for _, want := range []string{
+		"<h1>app</h1>\n",
+		`<tr><td><a href="app-get.html">get</a></td><td><code>g</code></td><td>Get the quotes of the specified isins</td></tr>`,
+		"<tr><td><code>-c, --config</code></td><td>string</td><td></td><td>config file (environment: QUOTES_CONFIG)</td></tr>",
+	} {
+		if !strings.Contains(docs["app.html"], want) {
+			t.Errorf("app.html: got\n%s\nwant substring %q", docs["app.html"], want)
+		}
+	}
+	if want := `<li><a href="app.html">app</a> - Manage the quotes of the isins</li>`; !strings.Contains(docs["app-get.html"], want) {
+		t.Errorf("app-get.html: got\n%s\nwant substring %q", docs["app-get.html"], want)
+	}
+}
+
+func TestWriteDocs(t *testing.T) {
+	dir := t.TempDir()
+	docs, err := MarkdownDocs("app", testQuotesApp())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteDocs(dir, docs); err != nil {
+		t.Fatalf("WriteDocs() error = %v, want nil", err)
+	}
+
+	// check the documentation is up to date
+	for name, content := range docs {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s is stale", name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parse parses the arguments of the flag set fs, as fs.Parse,
+// then checks the required flags (see CheckRequired).
+//
+// A parse error is returned as an *Error of kind ErrFlagParse, wrapping
+// the error of fs.Parse, with the offending flag as Token and Printed true
+// (the flag set has already printed the message); a missing
+// required flag as an *Error of kind ErrRequiredFlag. The flag.ErrHelp
+// error is returned as is.
+// The parse errors of fs.Parse returned by a command are converted to the
+// same *Error by the dispatch, but the required flags are not checked.
+//
+// The invalid values of the secret flags (see Secret) are redacted, both in
+// the returned error and in the message printed by the flag set. Parse must
+// be used in place of fs.Parse for this: the flag package builds the message
+// `invalid value "value" for flag -name` itself, with the value as given.
+// With the flag.PanicOnError error handling, the panic value is not redacted.
+func Parse(fs *flag.FlagSet, arguments []string) error {
+	// redact the messages printed by the flag set
+	out := fs.Output()
+	fs.SetOutput(&redactWriter{fs: fs, w: out})
+	err := fs.Parse(arguments)
+	fs.SetOutput(out)
+
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		msg := redactParseError(fs, err.Error())
+		if msg != err.Error() {
+			// don't leak the secret through Unwrap
+			err = errors.New(msg)
+		}
+		return &Error{
+			Path:    fs.Name(),
+			Token:   parseErrorToken(msg),
+			Kind:    ErrFlagParse,
+			Err:     err,
+			Printed: true,
+			msg:     fs.Name() + ": " + msg,
+		}
+	}
+	return CheckRequired(fs)
+}
+
+// redactWriter writes to w the messages printed by the flag set fs,
+// with the invalid values of the secret flags redacted.
+type redactWriter struct {
+	fs *flag.FlagSet
+	w  io.Writer
+}
+
+// Write method of io.Writer interface.
+func (rw *redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, redactParseError(rw.fs, string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// invalidValueFlag splits the message of the flag package
+// `invalid value "value" for flag -name: err`, or
+// `invalid boolean value "value" for -name: err`, in the quoted value
+// and the name of the flag. It returns ok false if msg has another format.
+func invalidValueFlag(msg string) (quoted, name string, ok bool) {
+	for _, f := range []struct{ prefix, forFlag string }{
+		{"invalid value ", " for flag -"},
+		{"invalid boolean value ", " for -"},
+	} {
+		if !strings.HasPrefix(msg, f.prefix) {
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(msg[len(f.prefix):])
+		if err != nil {
+			return "", "", false
+		}
+		rest := msg[len(f.prefix)+len(quoted):]
+		if !strings.HasPrefix(rest, f.forFlag) {
+			return "", "", false
+		}
+		return quoted, beforeColon(rest[len(f.forFlag):]), true
+	}
+	return "", "", false
+}
+
+// beforeColon returns the part of s before the first ':', if any.
+func beforeColon(s string) string {
+	if j := strings.Index(s, ":"); j >= 0 {
+		return s[:j]
+	}
+	return s
+}
+
+// redactParseError returns the parse error message msg
+// with the invalid value of a secret flag redacted.
+func redactParseError(fs *flag.FlagSet, msg string) string {
+	quoted, name, ok := invalidValueFlag(msg)
+	if !ok {
+		return msg
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return msg
+	}
+	if ov, ok := f.Value.(*optValue); !ok || !ov.info.secret {
+		return msg
+	}
+	return strings.Replace(msg, quoted, strconv.Quote(Redacted), 1)
+}
+
+// parseErrorToken returns the offending flag of the parse error message msg,
+// or "" if msg is not a parse error message of the flag package.
+func parseErrorToken(msg string) string {
+	if _, name, ok := invalidValueFlag(msg); ok {
+		return "-" + name
+	}
+	if rest := strings.TrimPrefix(msg, "invalid boolean flag "); rest != msg {
+		return "-" + beforeColon(rest)
+	}
+	for _, prefix := range []string{
+		"flag provided but not defined: ",
+		"flag needs an argument: ",
+		"bad flag syntax: ",
+	} {
+		if strings.HasPrefix(msg, prefix) {
+			return msg[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// flagParseError returns the error err of the command with full name path
+// as an *Error of kind ErrFlagParse, if it is a parse error of the flag
+// package: the command parsed its flags by fs.Parse, in place of Parse.
+// The flag set has already printed the message. Other errors,
+// and the errors that are already an *Error, are returned as is.
+func flagParseError(path string, err error) error {
+	var fe *Error
+	if err == nil || errors.As(err, &fe) {
+		return err
+	}
+	msg := err.Error()
+	token := parseErrorToken(msg)
+	if token == "" {
+		return err
+	}
+	return &Error{
+		Path:    path,
+		Token:   token,
+		Kind:    ErrFlagParse,
+		Err:     err,
+		Printed: true,
+		msg:     path + ": " + msg,
+	}
+}
@@ -0,0 +1,107 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	newFlagSet := func(out *strings.Builder) *flag.FlagSet {
+		fs := flag.NewFlagSet("app get", flag.ContinueOnError)
+		fs.SetOutput(out)
+		fs.Usage = func() {}
+		AliasedIntVar(fs, new(int), "workers,w", 1, "number of workers")
+		AliasedIntVar(fs, new(int), "pin", 0, "pin code", Secret())
+		AliasedStringVar(fs, new(string), "output,o", "", "output file", Required())
+		return fs
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		kind     error
+		token    string
+		wantMsg  string
+		wantOut  string
+		wantHelp bool
+	}{
+		{
+			name: "ok",
+			args: []string{"-o", "out.txt"},
+		},
+		{
+			name:    "undefined",
+			args:    []string{"-x"},
+			kind:    ErrFlagParse,
+			token:   "-x",
+			wantMsg: "app get: flag provided but not defined: -x",
+			wantOut: "flag provided but not defined: -x\n",
+		},
+		{
+			name:    "invalid-value",
+			args:    []string{"-w", "many"},
+			kind:    ErrFlagParse,
+			token:   "-w",
+			wantMsg: `app get: invalid value "many" for flag -w: parse error`,
+			wantOut: "invalid value \"many\" for flag -w: parse error\n",
+		},
+		{
+			name:    "secret",
+			args:    []string{"--pin", "s3cr3t"},
+			kind:    ErrFlagParse,
+			token:   "-pin",
+			wantMsg: `app get: invalid value "********" for flag -pin: ` + ErrInvalidSecret.Error(),
+			wantOut: "invalid value \"********\" for flag -pin: " + ErrInvalidSecret.Error() + "\n",
+		},
+		{
+			name:    "required",
+			args:    []string{},
+			kind:    ErrRequiredFlag,
+			token:   "--output",
+			wantMsg: "app get: required flag not passed: --output",
+		},
+		{
+			name:     "help",
+			args:     []string{"-h"},
+			wantHelp: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := Parse(newFlagSet(&out), tt.args)
+			if tt.wantHelp {
+				if err != flag.ErrHelp {
+					t.Fatalf("want flag.ErrHelp, got %v", err)
+				}
+				return
+			}
+			if tt.kind == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("want *Error, got %T: %v", err, err)
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("want kind %v, got %v", tt.kind, e.Kind)
+			}
+			if e.Token != tt.token {
+				t.Errorf("Token = %q, want %q", e.Token, tt.token)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("output = %q, want %q", got, tt.wantOut)
+			}
+			if ExitCode(err) != ExitUsage {
+				t.Errorf("ExitCode = %d, want %d", ExitCode(err), ExitUsage)
+			}
+		})
+	}
+}
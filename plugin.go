@@ -0,0 +1,121 @@
+package flagx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// pluginGroup is the heading of the plugins in the help.
+const pluginGroup = "Plugin commands"
+
+// PluginExitError is the error returned when a plugin exits with
+// a non-zero status. The plugin is expected to have reported its own
+// failure: RunMain does not print the error, and exits with the same status.
+type PluginExitError struct {
+	Path   string // full name of the plugin command (example: "app foo")
+	Plugin string // path of the plugin executable
+	Code   int    // exit status of the plugin
+}
+
+func (e *PluginExitError) Error() string {
+	return fmt.Sprintf("%s: plugin %s exited with status %d", e.Path, filepath.Base(e.Plugin), e.Code)
+}
+
+// ExitCode returns the exit status of the plugin.
+func (e *PluginExitError) ExitCode() int { return e.Code }
+
+// pluginPrefix returns the prefix of the names of the plugin executables
+// of the command with full name fullname (example: "app-get-").
+func pluginPrefix(fullname string) string {
+	return strings.ReplaceAll(fullname, " ", "-") + "-"
+}
+
+// lookPlugin returns the path of the executable of the plugin name
+// of the command, searched in PluginDirs and then in PATH.
+func (cmd *Command) lookPlugin(fullname, name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	file := pluginPrefix(fullname) + name
+	for _, dir := range cmd.PluginDirs {
+		if path, err := exec.LookPath(filepath.Join(dir, file)); err == nil {
+			return path, true
+		}
+	}
+	if path, err := exec.LookPath(file); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// runPlugin executes the plugin at path, with full name fullname,
+// forwarding the arguments and the standard input, output and error.
+func runPlugin(ds *dispatchState, fullname, path string, arguments []string) error {
+	c := exec.CommandContext(ds.ctx, path, arguments...)
+	c.Stdin, c.Stdout, c.Stderr = Stdin, Stdout, Stderr
+	err := c.Run()
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && ee.ExitCode() > 0 {
+		return &PluginExitError{Path: fullname, Plugin: path, Code: ee.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", fullname, err)
+	}
+	return nil
+}
+
+// plugins returns the names of the plugins of the command, found
+// in PluginDirs and in PATH, that are not shadowed by the sub-commands scs
+// or by the user aliases, and that are not plugins of the sub-commands.
+// The result is sorted.
+func (cmd *Command) plugins(fullname string, scs []*subCommand) []string {
+	if !cmd.Plugins {
+		return nil
+	}
+	prefix := pluginPrefix(fullname)
+	dirs := append(append([]string{}, cmd.PluginDirs...), filepath.SplitList(os.Getenv("PATH"))...)
+
+	found := map[string]bool{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			if _, ok := cmd.lookPlugin(fullname, name[len(prefix):]); ok {
+				found[name[len(prefix):]] = true
+			}
+		}
+	}
+
+	res := []string{}
+	for name := range found {
+		_, isAlias := cmd.UserAliases[name]
+		shadowed := isAlias
+		for _, sc := range scs {
+			shadowed = shadowed || contains(sc.names, name)
+			// "<sub-command>-<name>" is a plugin of the sub-command
+			for _, n := range sc.names {
+				shadowed = shadowed || strings.HasPrefix(name, n+"-")
+			}
+		}
+		if !shadowed {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,166 @@
+package flagx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writePlugins writes the shell scripts files to a new directory,
+// and returns the directory.
+func writePlugins(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on windows")
+	}
+	dir := writeFiles(t, files)
+	for name := range files {
+		if err := os.Chmod(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// withStdio redefines Stdin, Stdout and Stderr for the test.
+func withStdio(t *testing.T, stdin string) (stdout, stderr *strings.Builder) {
+	t.Helper()
+	oldStdin, oldStdout, oldStderr := Stdin, Stdout, Stderr
+	t.Cleanup(func() { Stdin, Stdout, Stderr = oldStdin, oldStdout, oldStderr })
+	stdout, stderr = &strings.Builder{}, &strings.Builder{}
+	Stdin, Stdout, Stderr = strings.NewReader(stdin), stdout, stderr
+	return stdout, stderr
+}
+
+func TestCommand_Plugins(t *testing.T) {
+	dir := writePlugins(t, map[string]string{
+		"app-hello":     "#!/bin/sh\necho \"hello $*\"\nread line\necho \"stdin: $line\" >&2\n",
+		"app-fail":      "#!/bin/sh\necho failed >&2\nexit 3\n",
+		"app-get":       "#!/bin/sh\necho plugin get\n",
+		"app-get-isins": "#!/bin/sh\necho plugin isins\n",
+		"app-data.txt":  "not executable",
+	})
+	if err := os.Chmod(filepath.Join(dir, "app-data.txt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", "")
+
+	var getArgs []string
+	newApp := func(plugins bool) *Command {
+		return &Command{
+			Plugins:    plugins,
+			PluginDirs: []string{dir},
+			SubCmd: map[string]*Command{
+				"get": {
+					Short: "Get the quotes",
+					ParseExec: func(name string, arguments []string) error {
+						getArgs = arguments
+						return nil
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("run", func(t *testing.T) {
+		stdout, stderr := withStdio(t, "input\n")
+		if err := newApp(true).run("app", []string{"hello", "a", "b"}); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := stdout.String(), "hello a b\n"; got != want {
+			t.Errorf("stdout: got %q, want %q", got, want)
+		}
+		if got, want := stderr.String(), "stdin: input\n"; got != want {
+			t.Errorf("stderr: got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		withStdio(t, "")
+		err := newApp(true).run("app", []string{"fail"})
+		var pe *PluginExitError
+		if !errors.As(err, &pe) {
+			t.Fatalf("want *PluginExitError, got %T: %v", err, err)
+		}
+		if pe.Code != 3 || ExitCode(err) != 3 {
+			t.Errorf("exit code: got %d, want 3", ExitCode(err))
+		}
+		if want := "app fail: plugin app-fail exited with status 3"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("builtin precedence", func(t *testing.T) {
+		stdout, _ := withStdio(t, "")
+		if err := newApp(true).run("app", []string{"get", "x"}); err != nil {
+			t.Fatal(err)
+		}
+		if stdout.Len() != 0 || len(getArgs) != 1 {
+			t.Errorf("the plugin shadowed the sub-command: stdout %q", stdout.String())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		withStdio(t, "")
+		err := newApp(false).run("app", []string{"hello"})
+		if !errors.Is(err, ErrCommandNotFound) {
+			t.Errorf("error: got %v, want ErrCommandNotFound", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		withStdio(t, "")
+		for _, name := range []string{"data.txt", "missing", "../app-hello"} {
+			err := newApp(true).run("app", []string{name})
+			if !errors.Is(err, ErrCommandNotFound) {
+				t.Errorf("%s: error: got %v, want ErrCommandNotFound", name, err)
+			}
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		var buf strings.Builder
+		if err := PrintHelp(&buf, "app", newApp(true)); err != nil {
+			t.Fatal(err)
+		}
+		want := `Usage:
+    app <command> [options]
+
+Available commands:
+    get    Get the quotes
+
+Plugin commands:
+    fail
+    hello
+`
+		if got := buf.String(); got != want {
+			t.Errorf("PrintHelp: got\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+func TestRunMain_plugin(t *testing.T) {
+	dir := writePlugins(t, map[string]string{
+		"app-fail": "#!/bin/sh\necho failed >&2\nexit 3\n",
+	})
+	t.Setenv("PATH", dir)
+	_, stderr := withStdio(t, "")
+
+	oldExit, oldArgs := exit, os.Args
+	defer func() { exit, os.Args = oldExit, oldArgs }()
+	var code int
+	exit = func(c int) { code = c }
+	os.Args = []string{"app", "fail"}
+
+	RunMain(&Command{Plugins: true})
+
+	if code != 3 {
+		t.Errorf("exit code: got %d, want 3", code)
+	}
+	if got, want := stderr.String(), "failed\n"; got != want {
+		t.Errorf("stderr: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package flagx
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+)
+
+// PanicError is the error returned by a command that panicked,
+// when the panics are recovered (see Recover).
+type PanicError struct {
+	Path      string      // full name of the command (example: "app import")
+	Value     interface{} // value passed to panic
+	Stack     []byte      // stack trace of the goroutine that panicked
+	CrashFile string      // path of the crash file with the stack trace, if written
+	CrashErr  error       // error writing the crash file, if any
+}
+
+func (e *PanicError) Error() string {
+	msg := fmt.Sprintf("%s: panic: %v", e.Path, e.Value)
+	switch {
+	case e.CrashFile != "":
+		msg += fmt.Sprintf(" (stack trace written to %s)", e.CrashFile)
+	case e.CrashErr != nil:
+		msg += fmt.Sprintf(" (cannot write the crash file: %v)", e.CrashErr)
+	}
+	return msg
+}
+
+// Unwrap returns the value passed to panic, if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover returns a middleware that recovers the panics of the commands,
+// hooks included, and returns them as *PanicError. Recovery is opt-in:
+// add the middleware to the root command to enable it for all the commands.
+//
+// If crashDir is not empty, the stack trace is also written to a new crash
+// file in crashDir, named after the command (example: "app-import-crash-*.log").
+func Recover(crashDir string) Middleware {
+	return func(next ParseExecFunc) ParseExecFunc {
+		return func(fullname string, arguments []string) (err error) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				pe := &PanicError{Path: fullname, Value: v, Stack: debug.Stack()}
+				if crashDir != "" {
+					pe.CrashFile, pe.CrashErr = writeCrashFile(crashDir, pe)
+				}
+				err = pe
+			}()
+			return next(fullname, arguments)
+		}
+	}
+}
+
+// writeCrashFile writes the command, the panic value and the stack trace
+// of pe to a new file in dir, and returns the path of the file.
+func writeCrashFile(dir string, pe *PanicError) (string, error) {
+	f, err := os.CreateTemp(dir, strings.ReplaceAll(pe.Path, " ", "-")+"-crash-*.log")
+	if err != nil {
+		return "", err
+	}
+	_, err = fmt.Fprintf(f, "command: %s\npanic: %v\n\n%s", pe.Path, pe.Value, pe.Stack)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
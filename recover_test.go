@@ -0,0 +1,95 @@
+package flagx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	errBoom := errors.New("boom")
+	newApp := func(crashDir string) *Command {
+		return &Command{
+			Middleware: []Middleware{Recover(crashDir)},
+			SubCmd: map[string]*Command{
+				"import,i": {
+					ParseExec: func(string, []string) error { panic("index out of range") },
+				},
+				"export": {
+					ParseExec: func(string, []string) error { panic(errBoom) },
+				},
+				"get": {
+					ParseExec: func(string, []string) error { return nil },
+				},
+			},
+		}
+	}
+
+	t.Run("value", func(t *testing.T) {
+		err := newApp("").run("app", []string{"i"})
+		var pe *PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("want *PanicError, got %T: %v", err, err)
+		}
+		if pe.Path != "app import" || pe.Value != "index out of range" {
+			t.Errorf("got {%q %v}", pe.Path, pe.Value)
+		}
+		if want := "app import: panic: index out of range"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+		if !strings.Contains(string(pe.Stack), "recover_test.go") {
+			t.Errorf("stack does not contain the panicking function:\n%s", pe.Stack)
+		}
+		if ExitCode(err) != ExitFailure {
+			t.Errorf("ExitCode = %d, want %d", ExitCode(err), ExitFailure)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := newApp("").run("app", []string{"export"})
+		if !errors.Is(err, errBoom) {
+			t.Errorf("errors.Is(err, errBoom) = false: %v", err)
+		}
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		if err := newApp("").run("app", []string{"get"}); err != nil {
+			t.Errorf("error: got %v, want nil", err)
+		}
+	})
+
+	t.Run("crash file", func(t *testing.T) {
+		dir := t.TempDir()
+		err := newApp(dir).run("app", []string{"import"})
+		var pe *PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("want *PanicError, got %T: %v", err, err)
+		}
+		if pe.CrashErr != nil {
+			t.Fatal(pe.CrashErr)
+		}
+		if filepath.Dir(pe.CrashFile) != dir || !strings.HasPrefix(filepath.Base(pe.CrashFile), "app-import-crash-") {
+			t.Errorf("CrashFile = %q", pe.CrashFile)
+		}
+		if !strings.Contains(err.Error(), "stack trace written to "+pe.CrashFile) {
+			t.Errorf("Error() = %q", err.Error())
+		}
+		b, err := os.ReadFile(pe.CrashFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(b), "command: app import\npanic: index out of range\n\ngoroutine ") {
+			t.Errorf("crash file:\n%s", b)
+		}
+	})
+
+	t.Run("crash file error", func(t *testing.T) {
+		err := newApp(filepath.Join(t.TempDir(), "missing")).run("app", []string{"import"})
+		var pe *PanicError
+		if !errors.As(err, &pe) || pe.CrashErr == nil || pe.CrashFile != "" {
+			t.Fatalf("want *PanicError with CrashErr, got %v", err)
+		}
+	})
+}
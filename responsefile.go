@@ -0,0 +1,97 @@
+package flagx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// flagx response files errors
+var (
+	ErrResponseFile      = errors.New("invalid response file")
+	ErrResponseFileDepth = errors.New("response files nested too deeply")
+)
+
+// MaxResponseFileDepth is the maximum nesting level of the response files
+// expanded by ExpandResponseFiles.
+var MaxResponseFileDepth = 10
+
+// ExpandResponseFiles returns the arguments with each "@path" argument
+// replaced by the arguments read from the file at path.
+//
+// The file is split into arguments with shell-like rules:
+// usually one argument per line, '#' comments, single and double quotes
+// and backslash escapes. A response file can contain "@path" arguments too,
+// whose relative paths are resolved from the directory of the file,
+// up to MaxResponseFileDepth nesting levels.
+//
+// An argument beginning with "@@" is replaced by the argument without
+// the first '@', to pass a literal '@' argument.
+// The arguments after the "--" terminator are not expanded.
+//
+// The errors found in a response file specify the path and line of the error.
+func ExpandResponseFiles(arguments []string) ([]string, error) {
+	res := []string{}
+	for j, arg := range arguments {
+		if arg == "--" {
+			return append(res, arguments[j:]...), nil
+		}
+		args, err := expandResponseFile(arg, "", 0)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, args...)
+	}
+	return res, nil
+}
+
+// expandResponseFile returns the expansion of a single argument.
+// dir is the directory used to resolve a relative path,
+// and depth is the nesting level of the file containing the argument
+// (0 for the command line).
+func expandResponseFile(arg, dir string, depth int) ([]string, error) {
+	switch {
+	case strings.HasPrefix(arg, "@@"):
+		return []string{arg[1:]}, nil
+
+	case len(arg) > 1 && arg[0] == '@':
+		if depth >= MaxResponseFileDepth {
+			return nil, ErrResponseFileDepth
+		}
+		path := arg[1:]
+		if dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return readResponseFile(path, depth+1)
+	}
+	return []string{arg}, nil
+}
+
+// readResponseFile returns the expanded arguments of the response file at path.
+// The errors not already positioned are prefixed by the path and line of the argument.
+func readResponseFile(path string, depth int) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := tokenize(string(data))
+	if err != nil {
+		return nil, wrapErrorf(ErrResponseFile, "%s:%s", path, err)
+	}
+
+	res := []string{}
+	for _, t := range tokens {
+		args, err := expandResponseFile(t.value, filepath.Dir(path), depth)
+		if err != nil {
+			var sfe *simpleflagError
+			if !errors.As(err, &sfe) {
+				err = wrapErrorf(err, "%s:%d: %s", path, t.line, err)
+			}
+			return nil, err
+		}
+		res = append(res, args...)
+	}
+	return res, nil
+}
@@ -0,0 +1,145 @@
+package flagx
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes the files in a temporary directory and returns the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func Test_ExpandResponseFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"isins.txt":     "# list of isins\nisin1\nisin2 # comment\n'isin 3'\n",
+		"args.txt":      "get\n--isins\n@sub/more.txt\n",
+		"sub/more.txt":  "isin4\n",
+		"loop.txt":      "@loop.txt\n",
+		"bad-quote.txt": "a\n\"b\n",
+		"missing.txt":   "a\n@not-found.txt\n",
+	})
+	path := func(name string) string { return "@" + filepath.Join(dir, name) }
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       []string
+		wantErr    error
+		wantErrMsg string
+	}{
+		{
+			name: "no response files",
+			args: []string{"get", "-i", "isin1"},
+			want: []string{"get", "-i", "isin1"},
+		},
+		{
+			name: "response file",
+			args: []string{"get", path("isins.txt"), "-n"},
+			want: []string{"get", "isin1", "isin2", "isin 3", "-n"},
+		},
+		{
+			name: "nested response files",
+			args: []string{path("args.txt")},
+			want: []string{"get", "--isins", "isin4"},
+		},
+		{
+			name: "escaped at",
+			args: []string{"@@user", "@"},
+			want: []string{"@user", "@"},
+		},
+		{
+			name: "terminator",
+			args: []string{"a", "--", path("isins.txt")},
+			want: []string{"a", "--", path("isins.txt")},
+		},
+		{
+			name:       "recursion",
+			args:       []string{path("loop.txt")},
+			wantErr:    ErrResponseFileDepth,
+			wantErrMsg: "loop.txt:1: response files nested too deeply",
+		},
+		{
+			name:       "syntax error",
+			args:       []string{path("bad-quote.txt")},
+			wantErr:    ErrResponseFile,
+			wantErrMsg: "bad-quote.txt:2:1: unterminated double quote",
+		},
+		{
+			name:       "nested file not found",
+			args:       []string{path("missing.txt")},
+			wantErr:    fs.ErrNotExist,
+			wantErrMsg: "missing.txt:2: open ",
+		},
+		{
+			name:    "file not found",
+			args:    []string{path("not-found.txt")},
+			wantErr: fs.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandResponseFiles(tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ExpandResponseFiles() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Errorf("ExpandResponseFiles() error = %q, wantErrMsg %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandResponseFiles() error = %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandResponseFiles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_run_ResponseFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"args.txt": "cmd1 -x\n"})
+
+	var got []string
+	app := &Command{
+		ResponseFiles: true,
+		SubCmd: map[string]*Command{
+			"cmd1": {
+				ParseExec: func(name string, arguments []string) error {
+					got = arguments
+					return nil
+				},
+			},
+		},
+	}
+
+	if err := app.run("app", []string{"@" + filepath.Join(dir, "args.txt"), "-y"}); err != nil {
+		t.Fatalf("run() error = %v, want nil", err)
+	}
+	if want := []string{"-x", "-y"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("run() arguments = %q, want %q", got, want)
+	}
+
+	err := app.run("app", []string{"@" + filepath.Join(dir, "not-found.txt")})
+	if !errors.Is(err, fs.ErrNotExist) || !strings.HasPrefix(err.Error(), "app: ") {
+		t.Errorf("run() error = %v, want app: %v", err, fs.ErrNotExist)
+	}
+}
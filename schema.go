@@ -0,0 +1,165 @@
+package flagx
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Schema is the machine-readable description of a command and its sub-commands,
+// exported as JSON by WriteJSON and read back by ReadSchema.
+// Hidden and deprecated commands and flags are included, and marked as such.
+type Schema struct {
+	Name           string        `json:"name"`                     // primary name of the command
+	Path           string        `json:"path"`                     // full name of the command (example: "app get")
+	Aliases        []string      `json:"aliases,omitempty"`        // aliases of the command
+	RetiredAliases []string      `json:"retiredAliases,omitempty"` // retired aliases of the command
+	Short          string        `json:"short,omitempty"`          // one-line description
+	Long           string        `json:"long,omitempty"`           // long description
+	Group          string        `json:"group,omitempty"`          // group of the command
+	Hidden         bool          `json:"hidden,omitempty"`         // the command is hidden
+	Deprecated     string        `json:"deprecated,omitempty"`     // deprecation message
+	Flags          []*FlagSchema `json:"flags,omitempty"`          // flags of the command
+	Commands       []*Schema     `json:"commands,omitempty"`       // sub-commands of the command
+}
+
+// FlagSchema is the machine-readable description of an aliased flag.
+type FlagSchema struct {
+	Name           string   `json:"name"`                     // primary name of the flag
+	Aliases        []string `json:"aliases,omitempty"`        // aliases of the flag
+	RetiredAliases []string `json:"retiredAliases,omitempty"` // retired aliases of the flag
+	Type           string   `json:"type"`                     // type of the value; "bool" for boolean flags
+	Default        string   `json:"default,omitempty"`        // default value; redacted for secret flags, if not zero
+	Usage          string   `json:"usage,omitempty"`          // usage string
+	Required       bool     `json:"required,omitempty"`       // the flag is required
+	Hidden         bool     `json:"hidden,omitempty"`         // the flag is hidden
+	Deprecated     string   `json:"deprecated,omitempty"`     // deprecation message
+	Negatable      bool     `json:"negatable,omitempty"`      // the "no-<name>" flags are defined
+	Secret         bool     `json:"secret,omitempty"`         // the value of the flag is secret
+	Env            string   `json:"env,omitempty"`            // environment variable bound to the flag
+	Enum           []string `json:"enum,omitempty"`           // accepted values
+}
+
+// NewSchema returns the schema of the root command app with name appname.
+func NewSchema(appname string, app *Command) (*Schema, error) {
+	return newSchema(appname, appname, nil, app)
+}
+
+// newSchema returns the schema of the command cmd, with full name fullname
+// and names in the parent (nil for the root command).
+func newSchema(name, fullname string, names []string, cmd *Command) (*Schema, error) {
+	s := &Schema{
+		Name:       name,
+		Path:       fullname,
+		Short:      cmd.Short,
+		Long:       cmd.Long,
+		Group:      cmd.Group,
+		Hidden:     cmd.Hidden,
+		Deprecated: cmd.Deprecated,
+	}
+	if len(names) > 1 {
+		s.Aliases = names[1:]
+		s.RetiredAliases = cmd.RetiredAliases
+	}
+
+	for _, d := range cmd.allFlagDefs(fullname) {
+		s.Flags = append(s.Flags, newFlagSchema(d))
+	}
+
+	scs, err := cmd.subCommands(fullname)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range scs {
+		child, err := newSchema(sc.primary(), fullname+" "+sc.primary(), sc.names, sc.cmd)
+		if err != nil {
+			return nil, err
+		}
+		s.Commands = append(s.Commands, child)
+	}
+	return s, nil
+}
+
+// newFlagSchema returns the schema of the flag.
+func newFlagSchema(d *flagDef) *FlagSchema {
+	fs := &FlagSchema{
+		Name:      d.names[0],
+		Aliases:   d.names[1:],
+		Type:      d.typ,
+		Default:   d.flag.DefValue,
+		Usage:     d.usage,
+		Negatable: d.negatable,
+	}
+	if fs.Type == "" {
+		fs.Type = "bool"
+	}
+	if len(fs.Aliases) == 0 {
+		fs.Aliases = nil
+	}
+	if _, ok := unwrapValue(d.flag.Value).(*SecretString); ok {
+		fs.Secret = true
+	}
+	if fi := d.info; fi != nil {
+		fs.RetiredAliases = fi.retiredAliases
+		fs.Required = fi.required
+		fs.Hidden = fi.hidden
+		fs.Deprecated = fi.deprecated
+		fs.Secret = fs.Secret || fi.secret
+		fs.Env = fi.env
+		fs.Enum = fi.enum
+	}
+	return fs
+}
+
+// WriteJSON writes the schema to w as indented JSON.
+func (s *Schema) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// ReadSchema reads a schema written by WriteJSON.
+func ReadSchema(r io.Reader) (*Schema, error) {
+	s := &Schema{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// names returns the primary name followed by the aliases of the command.
+func (s *Schema) names() []string {
+	return append([]string{s.Name}, s.Aliases...)
+}
+
+// names returns the primary name followed by the aliases of the flag.
+func (fs *FlagSchema) names() []string {
+	return append([]string{fs.Name}, fs.Aliases...)
+}
+
+// command returns the sub-command with the given name or alias, or nil.
+func (s *Schema) command(name string) *Schema {
+	for _, c := range s.Commands {
+		if contains(c.names(), name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// flag returns the flag with the given name or alias, or nil.
+func (s *Schema) flag(name string) *FlagSchema {
+	for _, f := range s.Flags {
+		if contains(f.names(), name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// Removed returns the breaking changes from the schema s to the current
+// schema, like removed commands, flags and aliases: each of them could break
+// the scripts using the previous version of the command. It is a shorthand
+// for BreakingChanges(Compare(s, current)).
+func (s *Schema) Removed(current *Schema) []*Change {
+	return BreakingChanges(Compare(s, current))
+}
@@ -0,0 +1,137 @@
+package flagx
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSchema(t *testing.T) {
+	s, err := NewSchema("app", testQuotesApp())
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v, want nil", err)
+	}
+
+	names := []string{}
+	for _, c := range s.Commands {
+		names = append(names, c.Path)
+	}
+	if want := []string{"app get", "app sources", "app tor"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("commands = %v, want %v", names, want)
+	}
+
+	if tor := s.command("tor"); tor.Deprecated == "" || !reflect.DeepEqual(tor.Aliases, []string{"t"}) {
+		t.Errorf("tor = %+v, want deprecated with alias t", tor)
+	}
+
+	get := s.command("get")
+	tests := []struct {
+		name string
+		want *FlagSchema
+	}{
+		{
+			name: "dry-run",
+			want: &FlagSchema{Name: "dry-run", Aliases: []string{"n"}, Type: "bool", Default: "false",
+				Usage: "perform a trial run with no request/updates made", Negatable: true},
+		},
+		{
+			name: "proxy",
+			want: &FlagSchema{Name: "proxy", Aliases: []string{"p"}, Type: "string",
+				Usage: "default proxy", Secret: true, Env: "QUOTES_PROXY"},
+		},
+		{
+			name: "database",
+			want: &FlagSchema{Name: "database", Aliases: []string{"d", "db"}, RetiredAliases: []string{"db"},
+				Type: "string", Usage: "sqlite3 database"},
+		},
+		{
+			name: "isins",
+			want: &FlagSchema{Name: "isins", Aliases: []string{"i"}, Type: "strings", Default: "[]",
+				Usage: "list of isins to get the quotes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := get.flag(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flag %q = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema_JSON(t *testing.T) {
+	s, err := NewSchema("app", testQuotesApp())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	if err := s.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v, want nil", err)
+	}
+	for _, want := range []string{
+		`"path": "app get"`,
+		`"env": "QUOTES_PROXY"`,
+		`"negatable": true`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteJSON(): got\n%s\nwant substring %q", buf.String(), want)
+		}
+	}
+
+	got, err := ReadSchema(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ReadSchema() error = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("ReadSchema(): got %+v, want %+v", got, s)
+	}
+
+	if _, err := ReadSchema(strings.NewReader("{")); err == nil {
+		t.Errorf("ReadSchema(): got nil error, want not nil")
+	}
+}
+
+func TestSchema_Removed(t *testing.T) {
+	old, err := NewSchema("app", testQuotesApp())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := testQuotesApp()
+	delete(app.SubCmd, "tor,t")
+	sources := app.SubCmd["sources,s"]
+	delete(app.SubCmd, "sources,s")
+	app.SubCmd["sources"] = sources
+	get := app.SubCmd["get,g"]
+	get.Flags = func(fs *flag.FlagSet) {
+		var database string
+		AliasedStringVar(fs, &database, "database,d", "", "sqlite3 database")
+	}
+	cur, err := NewSchema("app", app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`breaking: app get: flag --database: alias --db removed`,
+		`breaking: app get: flag --dry-run: removed`,
+		`breaking: app get: flag --isins: removed`,
+		`breaking: app get: flag --proxy: removed`,
+		`breaking: app get: flag --workers: removed`,
+		`breaking: app sources: alias "s" removed`,
+		`breaking: app tor: command removed`,
+	}
+	got := []string{}
+	for _, c := range old.Removed(cur) {
+		got = append(got, c.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Removed():\ngot  %q\nwant %q", got, want)
+	}
+
+	if got := old.Removed(old); len(got) != 0 {
+		t.Errorf("Removed() of same schema: got %q, want none", got)
+	}
+}
@@ -0,0 +1,118 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+)
+
+// Redacted is the string shown in place of the value of a secret flag.
+const Redacted = "********"
+
+// ErrInvalidSecret is returned in place of the error of a secret flag value,
+// whose message could contain the secret itself. See Parse for the redaction
+// of the value in the parse error.
+var ErrInvalidSecret = errors.New("invalid secret value")
+
+// redact returns Redacted if s is not empty, otherwise the empty string.
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// SecretString is a string flag.Value whose value is never shown:
+// the String method returns Redacted in place of the value.
+// Use string(s) to get the actual value.
+type SecretString string
+
+// String method of flag.Value interface.
+func (s SecretString) String() string { return redact(string(s)) }
+
+// GoString returns the redacted value also for the %#v format.
+func (s SecretString) GoString() string { return redact(string(s)) }
+
+// Set method of flag.Value interface.
+func (s *SecretString) Set(value string) error {
+	*s = SecretString(value)
+	return nil
+}
+
+// AliasedSecretVar defines a SecretString flag with specified names, default value, and usage string.
+// The specified usage string is used for the primary flag name only.
+// The usage string of a secondary flag name specifies that it is an alias of the primary name.
+// The argument p points to a SecretString variable in which to store the value of the flag.
+func AliasedSecretVar(fs *flag.FlagSet, p *SecretString, names string, value string, usage string, opts ...FlagOption) {
+	*p = SecretString(value)
+	aliasedVar(fs, names, usage, opts, func(name, usage string) {
+		fs.Var(p, name, usage)
+	})
+}
+
+// Secret option hides the value of the flag: wherever flagx renders it
+// (usage defaults, String method of the value, errors) Redacted is shown instead.
+// A zero default value, like "" or "0", is not redacted: it is not a secret.
+// The invalid values are redacted from the parse errors only if the flag set
+// is parsed with Parse: fs.Parse prints and returns them as given.
+func Secret() FlagOption {
+	return func(fi *flagInfo) {
+		fi.secret = true
+	}
+}
+
+// SecretFile option implies Secret and defines the "<name>-file" flag,
+// where name is the primary name of the flag, to read the value
+// from a file instead of the command line, so that it is not shown
+// in the process list. The "-" file name reads the value from Stdin.
+// The trailing newline of the file content is removed.
+func SecretFile() FlagOption {
+	return func(fi *flagInfo) {
+		fi.secret = true
+		fi.secretFile = true
+	}
+}
+
+// secretFileName returns the name of the flag used to read the secret from a file.
+func secretFileName(name string) string {
+	return name + "-file"
+}
+
+// secretFileUsage returns the usage string of the flag used to read the secret from a file.
+func secretFileUsage(primary string) string {
+	return "read the value of \"" + primary + "\" from `file` (\"-\" for standard input)"
+}
+
+// secretFileValue is the flag.Value of a "<name>-file" flag.
+// Setting it reads the content of the file and sets the secret flag.
+type secretFileValue struct {
+	name  string     // primary name of the secret flag
+	value flag.Value // value of the secret flag
+	path  string     // path of the last file read
+}
+
+// derivedFrom returns the primary name of the secret flag.
+func (v *secretFileValue) derivedFrom() string { return v.name }
+
+// String method of flag.Value interface.
+func (v *secretFileValue) String() string { return v.path }
+
+// Set method of flag.Value interface.
+func (v *secretFileValue) Set(path string) error {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	v.path = path
+	return v.value.Set(strings.TrimRight(string(data), "\r\n"))
+}
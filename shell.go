@@ -0,0 +1,147 @@
+package flagx
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shell is an interactive shell that executes the commands of a Command tree:
+// each line read is split into arguments with shell-like rules (as the
+// response files, see ExpandResponseFiles) and dispatched to the root command,
+// without its name. A line ending with an unquoted backslash continues on the
+// next line.
+//
+// Besides the commands of the tree, the shell handles:
+//
+//	help [command...]   the help of the root command, or of a command or topic
+//	history             the lines executed in the session
+//	exit, quit          the end of the session
+//
+// The errors are printed, and the session continues.
+type Shell struct {
+	Name    string    // name of the root command (example: "app")
+	App     *Command  // root command
+	Prompt  string    // prompt printed before each line; default Name + "> "
+	In      io.Reader // input of the shell; default Stdin
+	Out     io.Writer // output of the shell; default Stdout
+	History []string  // lines executed in the session, the most recent last
+}
+
+// shell built-in commands
+const (
+	shellExit    = "exit"
+	shellQuit    = "quit"
+	shellHistory = "history"
+)
+
+// Run reads and executes the lines of the input until the end of the input
+// or the exit command. During the session, the output of flag.CommandLine,
+// Stdout and Stderr are redefined as the output of the shell, so that the
+// help, the errors and the output of the plugins are written to it.
+func (sh *Shell) Run() error {
+	in, out := sh.In, sh.Out
+	if in == nil {
+		in = Stdin
+	}
+	if out == nil {
+		out = Stdout
+	}
+	prompt := sh.Prompt
+	if prompt == "" {
+		prompt = sh.Name + "> "
+	}
+
+	oldOutput, oldStdout, oldStderr := flag.CommandLine.Output(), Stdout, Stderr
+	flag.CommandLine.SetOutput(out)
+	Stdout, Stderr = out, out
+	defer func() {
+		flag.CommandLine.SetOutput(oldOutput)
+		Stdout, Stderr = oldStdout, oldStderr
+	}()
+
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, prompt)
+		line, _, ok := readContinuedLine(sc)
+		if !ok {
+			fmt.Fprintln(out)
+			return sc.Err()
+		}
+		if sh.execLine(out, line) {
+			return nil
+		}
+	}
+}
+
+// readContinuedLine reads a line from sc, joined with the next ones
+// if it ends with an unquoted backslash, and returns the number of lines read.
+// It returns false at the end of the input.
+func readContinuedLine(sc *bufio.Scanner) (string, int, bool) {
+	if !sc.Scan() {
+		return "", 0, false
+	}
+	line, n := sc.Text(), 1
+	for continuesLine(line) && sc.Scan() {
+		line += "\n" + sc.Text()
+		n++
+	}
+	return line, n, true
+}
+
+// execLine executes the line, printing the errors to out.
+// It returns true if the line is the exit command.
+func (sh *Shell) execLine(out io.Writer, line string) bool {
+	args, err := Split(line)
+	if err != nil {
+		PrintError(out, err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+	sh.History = append(sh.History, strings.TrimSpace(line))
+
+	switch {
+	case args[0] == shellExit || args[0] == shellQuit:
+		return true
+	case args[0] == shellHistory:
+		for j, h := range sh.History {
+			fmt.Fprintf(out, "%5d  %s\n", j+1, h)
+		}
+	case args[0] == helpCommandName && !sh.App.hasSubCmdName(helpCommandName):
+		err = sh.App.helpCommand(sh.Name).ParseExec(sh.Name+" "+helpCommandName, args[1:])
+	default:
+		err = sh.App.run(sh.Name, args)
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) && !isPrinted(err) {
+		PrintError(out, err)
+	}
+	return false
+}
+
+// Complete returns the completions of the last argument of the line,
+// for the tab-completion of a line editor. The completions of the first
+// argument include the shell built-in commands. See Complete.
+func (sh *Shell) Complete(line string) []string {
+	args, err := Split(line)
+	if err != nil {
+		return nil
+	}
+	if len(args) == 0 || strings.HasSuffix(line, " ") || strings.HasSuffix(line, "\t") {
+		args = append(args, "")
+	}
+
+	res := Complete(sh.Name, sh.App, args)
+	if len(args) == 1 {
+		builtins := []string{shellExit, shellHistory, shellQuit}
+		if !sh.App.HelpCommand && !sh.App.hasSubCmdName(helpCommandName) {
+			builtins = append(builtins, helpCommandName)
+		}
+		res = completions(append(res, builtins...), args[0])
+	}
+	return res
+}
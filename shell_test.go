@@ -0,0 +1,146 @@
+package flagx
+
+import (
+	"bufio"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShell_Run(t *testing.T) {
+	var calls [][]string
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get,g": {
+				Short: "Get the quotes",
+				ParseExec: func(name string, arguments []string) error {
+					fs := flag.NewFlagSet(name, flag.ContinueOnError)
+					fs.SetOutput(Stderr)
+					fs.Usage = func() {}
+					fs.Bool("n", false, "dry run")
+					if err := Parse(fs, arguments); err != nil {
+						return err
+					}
+					calls = append(calls, append([]string{name}, fs.Args()...))
+					return nil
+				},
+			},
+		},
+	}
+
+	input := `get isin1 'isin 2'
+# comment
+
+g -n isin3 \
+  isin4
+gte
+get -x
+help
+history
+echo 'unterminated
+exit
+get never
+`
+	var out strings.Builder
+	sh := &Shell{Name: "app", App: app, In: strings.NewReader(input), Out: &out}
+	if err := sh.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCalls := [][]string{
+		{"app get", "isin1", "isin 2"},
+		{"app get", "isin3", "isin4"},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("calls: got %q, want %q", calls, wantCalls)
+	}
+
+	wantOut := `app> app> app> app> app> error: app: command not found "gte" (did you mean "get"?)
+app> flag provided but not defined: -x
+app> Usage:
+    app <command> [options]
+
+Available commands:
+    get  Get the quotes
+app>     1  get isin1 'isin 2'
+    2  g -n isin3 \
+  isin4
+    3  gte
+    4  get -x
+    5  help
+    6  history
+app> error: 1:6: unterminated single quote
+app> `
+	if got := out.String(); got != wantOut {
+		t.Errorf("output: got\n%s\nwant\n%s", got, wantOut)
+	}
+	if len(sh.History) != 7 || sh.History[6] != "exit" {
+		t.Errorf("History: got %q", sh.History)
+	}
+}
+
+func TestShell_Run_eof(t *testing.T) {
+	var out strings.Builder
+	sh := &Shell{Name: "app", App: &Command{}, Prompt: "$ ", In: strings.NewReader(""), Out: &out}
+	if err := sh.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "$ \n"; got != want {
+		t.Errorf("output: got %q, want %q", got, want)
+	}
+}
+
+func Test_readContinuedLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"continued", "echo a\\\nb\necho c", []string{"echo a\\\nb", "echo c"}},
+		{"escaped backslash", "echo a\\\\\necho b", []string{"echo a\\\\", "echo b"}},
+		{"escaped backslash continued", "echo a\\\\\\\nb", []string{"echo a\\\\\\\nb"}},
+		{"single quoted backslash", "echo 'a\\'\necho b", []string{"echo 'a\\'", "echo b"}},
+		{"comment", "echo a # \\\necho b", []string{"echo a # \\", "echo b"}},
+		{"last line", "echo a\\", []string{"echo a\\"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := bufio.NewScanner(strings.NewReader(tt.input))
+			got := []string{}
+			for {
+				line, _, ok := readContinuedLine(sc)
+				if !ok {
+					break
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lines: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShell_Complete(t *testing.T) {
+	sh := &Shell{Name: "app", App: &Command{
+		SubCmd: map[string]*Command{
+			"get":   {},
+			"hello": {},
+		},
+	}}
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", []string{"exit", "get", "hello", "help", "history", "quit"}},
+		{"h", []string{"hello", "help", "history"}},
+		{"get ", []string{}},
+		{"get 'unterminated", nil},
+	}
+	for _, tt := range tests {
+		if got := sh.Complete(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package flagx
+
+import (
+	"io"
+	"os"
+)
+
+// Stdin is the reader used by flagx to read values from the standard input,
+// and the standard input of the plugins (see Command.Plugins).
+// It can be redefined for test purposes.
+var Stdin io.Reader = os.Stdin
+
+// Stderr is the writer used by flagx to print the errors,
+// and the standard error of the plugins (see Command.Plugins).
+// It can be redefined for test purposes.
+var Stderr io.Writer = os.Stderr
+
+// Stdout is the writer used by flagx as the standard output
+// of the plugins (see Command.Plugins).
+// It can be redefined for test purposes.
+var Stdout io.Writer = os.Stdout
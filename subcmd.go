@@ -0,0 +1,152 @@
+package flagx
+
+import (
+	"sort"
+	"strings"
+)
+
+// subCommand is a sub-command of a Command, with its names.
+type subCommand struct {
+	key   string   // key of the sub-command in the SubCmd map
+	names []string // primary name followed by the aliases
+	cmd   *Command // the sub-command
+}
+
+// subCommands returns the sub-commands of the command sorted by primary name,
+// including the "help" sub-command added by HelpCommand.
+// fullname is the full name of the command, used in the returned error
+// if the key of a sub-command has no valid names.
+func (cmd *Command) subCommands(fullname string) ([]*subCommand, error) {
+	res := make([]*subCommand, 0, len(cmd.SubCmd))
+
+	for key, subcmd := range cmd.SubCmd {
+		ns := splitTrimSpace(key, ",")
+		if len(ns) == 0 {
+			return nil, newError(ErrInvalidCommandName, fullname, key)
+		}
+		res = append(res, &subCommand{key, ns, subcmd})
+	}
+
+	if cmd.HelpCommand && !cmd.hasSubCmdName(helpCommandName) {
+		res = append(res, &subCommand{helpCommandName, []string{helpCommandName}, cmd.helpCommand(fullname)})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].names[0] < res[j].names[0] })
+	return res, nil
+}
+
+// findSubCommand returns the sub-command of scs with the given name
+// (primary name or alias), or nil if not found.
+func findSubCommand(scs []*subCommand, name string) *subCommand {
+	for _, sc := range scs {
+		if contains(sc.names, name) {
+			return sc
+		}
+	}
+	return nil
+}
+
+// primary returns the primary name of the sub-command.
+func (sc *subCommand) primary() string {
+	return sc.names[0]
+}
+
+// hidden checks if the sub-command is hidden.
+// A deprecated sub-command is also hidden.
+func (sc *subCommand) hidden() bool {
+	return sc.cmd.Hidden || sc.cmd.Deprecated != ""
+}
+
+// visibleNames returns the names of the sub-command,
+// without the hidden and retired aliases.
+// The primary name is always returned.
+func (sc *subCommand) visibleNames() []string {
+	res := []string{sc.primary()}
+	for _, n := range sc.names[1:] {
+		if !contains(sc.cmd.HiddenAliases, n) && !contains(sc.cmd.RetiredAliases, n) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// visibleSubCommands returns the sub-commands that are not hidden.
+func visibleSubCommands(scs []*subCommand) []*subCommand {
+	res := []*subCommand{}
+	for _, sc := range scs {
+		if !sc.hidden() {
+			res = append(res, sc)
+		}
+	}
+	return res
+}
+
+// commandNode is a node of the tree of the visible commands,
+// used by the documentation generators.
+type commandNode struct {
+	fullname string         // full name of the command (example: "app get")
+	names    []string       // names of the command in the parent; nil for the root
+	cmd      *Command       // the command
+	parent   *commandNode   // parent node; nil for the root
+	children []*commandNode // visible sub-commands sorted by primary name
+}
+
+// commandTree returns the tree of the visible commands
+// of the root command cmd with name appname.
+func commandTree(appname string, cmd *Command) (*commandNode, error) {
+	root := &commandNode{fullname: appname, cmd: cmd}
+	return root, root.addChildren()
+}
+
+// addChildren adds the visible sub-commands of the node, recursively.
+func (n *commandNode) addChildren() error {
+	scs, err := n.cmd.subCommands(n.fullname)
+	if err != nil {
+		return err
+	}
+	for _, sc := range visibleSubCommands(scs) {
+		child := &commandNode{
+			fullname: n.fullname + " " + sc.primary(),
+			names:    sc.visibleNames(),
+			cmd:      sc.cmd,
+			parent:   n,
+		}
+		if err := child.addChildren(); err != nil {
+			return err
+		}
+		n.children = append(n.children, child)
+	}
+	return nil
+}
+
+// walk calls fn for the node and all its descendants, in depth-first order.
+func (n *commandNode) walk(fn func(n *commandNode) error) error {
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, c := range n.children {
+		if err := c.walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// siblings returns the other sub-commands of the parent of the node.
+func (n *commandNode) siblings() []*commandNode {
+	res := []*commandNode{}
+	if n.parent != nil {
+		for _, c := range n.parent.children {
+			if c != n {
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+// pageName returns the name of the documentation page of the node:
+// the full name with the spaces replaced by '-' (example: "app-get").
+func (n *commandNode) pageName() string {
+	return strings.ReplaceAll(n.fullname, " ", "-")
+}
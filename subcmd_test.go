@@ -0,0 +1,124 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestCommand_subCommands(t *testing.T) {
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"tor,t":           {Hidden: true},
+			"get,g,fetch":     {HiddenAliases: []string{"fetch"}},
+			"sources,s,src":   {RetiredAliases: []string{"src"}},
+			"config,cfg":      {Deprecated: "no more used"},
+			" import , imp ,": {},
+		},
+	}
+
+	scs, err := app.subCommands("app")
+	if err != nil {
+		t.Fatalf("subCommands() error = %v, want nil", err)
+	}
+
+	got := [][]string{}
+	for _, sc := range scs {
+		got = append(got, sc.names)
+	}
+	want := [][]string{
+		{"config", "cfg"},
+		{"get", "g", "fetch"},
+		{"import", "imp"},
+		{"sources", "s", "src"},
+		{"tor", "t"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("subCommands() names = %v, want %v", got, want)
+	}
+
+	got = [][]string{}
+	for _, sc := range visibleSubCommands(scs) {
+		got = append(got, sc.visibleNames())
+	}
+	want = [][]string{
+		{"get", "g"},
+		{"import", "imp"},
+		{"sources", "s"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("visibleSubCommands() names = %v, want %v", got, want)
+	}
+
+	app.SubCmd[" , "] = &Command{}
+	if _, err := app.subCommands("app"); !errors.Is(err, ErrInvalidCommandName) {
+		t.Errorf("subCommands() error = %v, want %v", err, ErrInvalidCommandName)
+	}
+}
+
+// testQuotesApp returns the command tree of a quotes app,
+// used to test the documentation generators.
+func testQuotesApp() *Command {
+	var (
+		config   string
+		dryrun   bool
+		proxy    string
+		workers  int
+		isins    []string
+		database string
+	)
+
+	return &Command{
+		Short: "Manage the quotes of the isins",
+		Flags: func(fs *flag.FlagSet) {
+			AliasedStringVar(fs, &config, "config,c", "", "config file", Env("QUOTES_CONFIG"))
+		},
+		SubCmd: map[string]*Command{
+			"get,g": {
+				Short:    "Get the quotes of the specified isins",
+				Long:     "Get the quotes of the specified isins.\n\nThe quotes are saved in the database.",
+				Examples: []string{"app get -i isin1,isin2 -w 4"},
+				Flags: func(fs *flag.FlagSet) {
+					AliasedBoolVar(fs, &dryrun, "dry-run,n", false, "perform a trial run with no request/updates made", Negatable())
+					AliasedStringVar(fs, &proxy, "proxy,p", "", "default proxy", Env("QUOTES_PROXY"), Secret())
+					AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+					AliasedStringsVar(fs, &isins, "isins,i", "list of isins to get the quotes")
+					AliasedStringVar(fs, &database, "database,d,db", "", "sqlite3 database", RetiredAliases("db"))
+				},
+			},
+			"sources,s": {
+				Short: "Show available sources",
+			},
+			"tor,t": {
+				Short:      "Checks if Tor network will be used",
+				Deprecated: `use "app get --check-tor"`,
+			},
+		},
+	}
+}
+
+func Test_commandTree(t *testing.T) {
+	root, err := commandTree("app", testQuotesApp())
+	if err != nil {
+		t.Fatalf("commandTree() error = %v, want nil", err)
+	}
+
+	got := []string{}
+	root.walk(func(n *commandNode) error {
+		got = append(got, n.pageName())
+		return nil
+	})
+	want := []string{"app", "app-get", "app-sources"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commandTree() pages = %v, want %v", got, want)
+	}
+
+	get := root.children[0]
+	if !reflect.DeepEqual(get.names, []string{"get", "g"}) {
+		t.Errorf("names = %v, want %v", get.names, []string{"get", "g"})
+	}
+	if sib := get.siblings(); len(sib) != 1 || sib[0].fullname != "app sources" {
+		t.Errorf("siblings = %v, want [app sources]", sib)
+	}
+}
@@ -0,0 +1,85 @@
+package flagx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// editDistance returns the optimal string alignment distance between a and b:
+// the number of insertions, deletions, substitutions and transpositions
+// of adjacent characters needed to change a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// minInt returns the minimum of the values.
+func minInt(v int, vs ...int) int {
+	for _, x := range vs {
+		if x < v {
+			v = x
+		}
+	}
+	return v
+}
+
+// suggest returns the names similar to name: the names beginning with name,
+// and the ones within a number of edits proportional to the length of name.
+func suggest(name string, names []string) []string {
+	maxDist := len(name) / 3
+	if maxDist < 1 {
+		maxDist = 1
+	}
+	res := []string{}
+	for _, n := range names {
+		if strings.HasPrefix(n, name) || editDistance(name, n) <= maxDist {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// suggestCommands returns the primary names of the visible sub-commands
+// similar to name.
+func suggestCommands(name string, scs []*subCommand) []string {
+	res := []string{}
+	for _, sc := range visibleSubCommands(scs) {
+		if len(suggest(name, sc.visibleNames())) > 0 {
+			res = append(res, sc.primary())
+		}
+	}
+	return res
+}
+
+// didYouMean returns the " (did you mean ...?)" suffix of the suggestions,
+// or the empty string if there are no suggestions.
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(suggestions))
+	for j, s := range suggestions {
+		quoted[j] = fmt.Sprintf("%q", s)
+	}
+	return " (did you mean " + strings.Join(quoted, " or ") + "?)"
+}
@@ -0,0 +1,73 @@
+package flagx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"get", "get", 0},
+		{"gte", "get", 1},
+		{"get", "", 3},
+		{"source", "sources", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func Test_suggest(t *testing.T) {
+	names := []string{"get", "sources", "set", "version"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"gte", []string{"get"}},
+		{"src", []string{}},
+		{"sou", []string{"sources"}},
+		{"versoin", []string{"version"}},
+		{"xyz", []string{}},
+	}
+	for _, tt := range tests {
+		if got := suggest(tt.name, names); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggest(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_notFoundSuggestions(t *testing.T) {
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get,g":   {ParseExec: func(string, []string) error { return nil }},
+			"sources": {ParseExec: func(string, []string) error { return nil }},
+			"debug":   {Hidden: true, ParseExec: func(string, []string) error { return nil }},
+		},
+	}
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"gte", `app: command not found "gte" (did you mean "get"?)`},
+		{"source", `app: command not found "source" (did you mean "sources"?)`},
+		{"debgu", `app: command not found "debgu"`},
+		{"xyz", `app: command not found "xyz"`},
+	}
+	for _, tt := range tests {
+		err := app.run("app", []string{tt.arg})
+		if !errors.Is(err, ErrCommandNotFound) {
+			t.Fatalf("%s: want ErrCommandNotFound, got %v", tt.arg, err)
+		}
+		if err.Error() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.arg, err.Error(), tt.want)
+		}
+	}
+}
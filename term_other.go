@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package flagx
+
+import "os"
+
+// terminalWidth returns the number of columns of the terminal f.
+// The size of the terminal is not detected on this platform,
+// so the ok result is always false.
+func terminalWidth(f *os.File) (width int, ok bool) {
+	return 0, false
+}
@@ -0,0 +1,195 @@
+package flagx
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// flagx execution errors
+var (
+	ErrTimeout          = errors.New("timeout")
+	ErrInterrupted      = errors.New("interrupted")
+	ErrTimeoutNoContext = errors.New("timeout requires ParseExecContext")
+)
+
+// ParseExecContextFunc is the signature of the function that is called
+// when a Command defined by ParseExecContext is executed.
+// ctx is cancelled when the timeout of the command expires or when
+// the command is interrupted.
+type ParseExecContextFunc func(ctx context.Context, fullname string, arguments []string) error
+
+// timeoutFlagName is the name of the flag that overrides the timeout of a command.
+const timeoutFlagName = "timeout"
+
+// timeoutUsage is the usage of the timeout flag.
+const timeoutUsage = "maximum duration of the command"
+
+// hasTimeoutFlag checks if flagx handles the "--timeout" flag of the command:
+// a "timeout" flag defined by Flags is left to the command.
+func (cmd *Command) hasTimeoutFlag() bool {
+	if cmd.ParseExecContext == nil || cmd.Timeout <= 0 {
+		return false
+	}
+	fs := cmd.flagSet("")
+	return fs == nil || fs.Lookup(timeoutFlagName) == nil
+}
+
+// flagSet returns a new flag set with the flags defined by Flags,
+// or nil if Flags is not defined. The warnings printed defining the flags
+// are discarded: the flag set of the command prints them.
+func (cmd *Command) flagSet(fullname string) *flag.FlagSet {
+	if cmd.Flags == nil {
+		return nil
+	}
+	out := flag.CommandLine.Output()
+	flag.CommandLine.SetOutput(io.Discard)
+	defer flag.CommandLine.SetOutput(out)
+
+	fs := flag.NewFlagSet(fullname, flag.ContinueOnError)
+	cmd.Flags(fs)
+	return fs
+}
+
+// timeoutArg returns the timeout of the command with full name fullname,
+// passed by the "-timeout" or "--timeout" flag among the flags of arguments,
+// or the Timeout of the command if the flag is not passed.
+// It also returns the arguments without the timeout flag.
+// As the flag package, the flags end at the first non-flag argument or "--";
+// the value of a non-boolean flag defined by Flags is not a flag.
+func (cmd *Command) timeoutArg(fullname string, arguments []string) (time.Duration, []string, error) {
+	fs := cmd.flagSet(fullname)
+	timeout := cmd.Timeout
+	res := make([]string, 0, len(arguments))
+	for j := 0; j < len(arguments); j++ {
+		arg := arguments[j]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			res = append(res, arguments[j:]...)
+			break
+		}
+		name := strings.TrimPrefix(arg[1:], "-")
+		if name != timeoutFlagName && !strings.HasPrefix(name, timeoutFlagName+"=") {
+			res = append(res, arg)
+			if fs == nil || strings.Contains(name, "=") || j+1 == len(arguments) {
+				continue
+			}
+			if f := fs.Lookup(name); f != nil && !isBoolValue(f.Value) {
+				// skip the value of the flag
+				j++
+				res = append(res, arguments[j])
+			}
+			continue
+		}
+		value := strings.TrimPrefix(strings.TrimPrefix(name, timeoutFlagName), "=")
+		if name == timeoutFlagName {
+			if j+1 == len(arguments) {
+				return 0, nil, &Error{Path: fullname, Token: arg, Kind: ErrFlagParse,
+					msg: fmt.Sprintf("%s: flag needs an argument: %s", fullname, arg)}
+			}
+			j++
+			value = arguments[j]
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, nil, &Error{Path: fullname, Token: arg, Kind: ErrFlagParse, Err: err,
+				msg: fmt.Sprintf("%s: invalid value %q for flag %s", fullname, value, flagArg(timeoutFlagName))}
+		}
+		timeout = d
+	}
+	return timeout, res, nil
+}
+
+// execute executes the command, with full name fullname and dispatch state ds,
+// wrapped by the hooks and the middlewares.
+func (cmd *Command) execute(fullname string, ds *dispatchState, arguments []string) error {
+	if cmd.ParseExecContext == nil {
+		if cmd.Timeout > 0 {
+			// a ParseExec command can't be stopped: don't ignore the timeout
+			return newError(ErrTimeoutNoContext, fullname, "")
+		}
+		return cmd.chain(ds.parents, cmd.ParseExec)(fullname, arguments)
+	}
+
+	timeout := cmd.Timeout
+	if cmd.hasTimeoutFlag() {
+		var err error
+		timeout, arguments, err = cmd.timeoutArg(fullname, arguments)
+		if err != nil {
+			return err
+		}
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ds.ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ds.ctx)
+	}
+	defer cancel()
+
+	var interrupted int32
+	if ds.signals {
+		stop := handleInterrupt(cancel, &interrupted)
+		defer stop()
+	}
+
+	err := cmd.chain(ds.parents, func(fullname string, arguments []string) error {
+		return cmd.ParseExecContext(ctx, fullname, arguments)
+	})(fullname, arguments)
+
+	switch {
+	case err == nil:
+		return nil
+	case atomic.LoadInt32(&interrupted) != 0:
+		return &Error{Path: fullname, Kind: ErrInterrupted, Err: err,
+			msg: fmt.Sprintf("%s: %s", fullname, ErrInterrupted)}
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && ds.ctx.Err() == nil:
+		return &Error{Path: fullname, Kind: ErrTimeout, Err: err,
+			msg: fmt.Sprintf("%s: %s after %s", fullname, ErrTimeout, timeout)}
+	}
+	return err
+}
+
+// signalNotify and signalStop are signal.Notify and signal.Stop.
+// They can be redefined for test purposes.
+var (
+	signalNotify = signal.Notify
+	signalStop   = signal.Stop
+)
+
+// handleInterrupt handles SIGINT until the returned stop function is called:
+// the first signal sets interrupted and calls cancel,
+// the second one terminates the program with ExitInterrupted.
+func handleInterrupt(cancel context.CancelFunc, interrupted *int32) (stop func()) {
+	c := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	signalNotify(c, os.Interrupt)
+
+	go func() {
+		select {
+		case <-c:
+		case <-done:
+			return
+		}
+		atomic.StoreInt32(interrupted, 1)
+		cancel()
+		select {
+		case <-c:
+			exit(ExitInterrupted)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signalStop(c)
+		close(done)
+	}
+}
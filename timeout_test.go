@@ -0,0 +1,247 @@
+package flagx
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_timeoutArg(t *testing.T) {
+	cmd := &Command{
+		Timeout: time.Minute,
+		Flags: func(fs *flag.FlagSet) {
+			fs.String("m", "", "message")
+			fs.Bool("v", false, "verbose")
+		},
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    time.Duration
+		wantArg []string
+		wantErr bool
+	}{
+		{"default", []string{"-n", "isin"}, time.Minute, []string{"-n", "isin"}, false},
+		{"long", []string{"--timeout", "5s", "-n"}, 5 * time.Second, []string{"-n"}, false},
+		{"short", []string{"-timeout", "1m30s"}, 90 * time.Second, []string{}, false},
+		{"equal", []string{"-n", "--timeout=2s", "isin"}, 2 * time.Second, []string{"-n", "isin"}, false},
+		{"after --", []string{"--", "--timeout=2s"}, time.Minute, []string{"--", "--timeout=2s"}, false},
+		{"other flag", []string{"--timeouts=2s"}, time.Minute, []string{"--timeouts=2s"}, false},
+		{"after positional", []string{"child", "--timeout", "x"}, time.Minute, []string{"child", "--timeout", "x"}, false},
+		{"flag value", []string{"-m", "--timeout"}, time.Minute, []string{"-m", "--timeout"}, false},
+		{"after flag value", []string{"-m", "msg", "-timeout=2s"}, 2 * time.Second, []string{"-m", "msg"}, false},
+		{"after bool flag", []string{"-v", "-timeout=2s", "x"}, 2 * time.Second, []string{"-v", "x"}, false},
+		{"missing value", []string{"--timeout"}, 0, nil, true},
+		{"invalid value", []string{"--timeout=soon"}, 0, nil, true},
+		{"negative value", []string{"--timeout=-1s"}, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := cmd.timeoutArg("app get", tt.args)
+			if tt.wantErr {
+				if !errors.Is(err, ErrFlagParse) {
+					t.Errorf("error: got %v, want ErrFlagParse", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || !reflect.DeepEqual(args, tt.wantArg) {
+				t.Errorf("got %v %q, want %v %q", got, args, tt.want, tt.wantArg)
+			}
+		})
+	}
+}
+
+// waitDone is a ParseExecContext that waits for the cancellation of the context.
+func waitDone(ctx context.Context, fullname string, arguments []string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCommand_Timeout(t *testing.T) {
+	var gotArgs []string
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get": {
+				Timeout: time.Hour,
+				ParseExecContext: func(ctx context.Context, fullname string, arguments []string) error {
+					gotArgs = arguments
+					return waitDone(ctx, fullname, arguments)
+				},
+			},
+			"fast": {
+				Timeout: time.Nanosecond,
+				ParseExecContext: func(ctx context.Context, fullname string, arguments []string) error {
+					<-ctx.Done()
+					return nil
+				},
+			},
+		},
+	}
+
+	err := app.run("app", []string{"get", "--timeout", "10ms", "isin"})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("error: got %v, want ErrTimeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error does not wrap context.DeadlineExceeded: %v", err)
+	}
+	if want := "app get: timeout after 10ms"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if ExitCode(err) != ExitTimeout {
+		t.Errorf("ExitCode = %d, want %d", ExitCode(err), ExitTimeout)
+	}
+	if !reflect.DeepEqual(gotArgs, []string{"isin"}) {
+		t.Errorf("arguments: got %q, want [isin]", gotArgs)
+	}
+
+	// a command that completes successfully is not timed out
+	if err := app.run("app", []string{"fast"}); err != nil {
+		t.Errorf("error: got %v, want nil", err)
+	}
+
+	// the cancellation of the parent context is not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = app.runContext(ctx, "app", []string{"get"}, false)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrTimeout) {
+		t.Errorf("error: got %v, want context.Canceled", err)
+	}
+}
+
+func TestCommand_Timeout_flags(t *testing.T) {
+	var timeout string
+	cmd := &Command{
+		Timeout: time.Second,
+		ParseExecContext: func(ctx context.Context, fullname string, arguments []string) error {
+			fs := flag.NewFlagSet(fullname, flag.ContinueOnError)
+			fs.StringVar(&timeout, "timeout", "", "timeout of the requests")
+			return fs.Parse(arguments)
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.String("timeout", "", "timeout of the requests")
+		},
+	}
+	if cmd.hasTimeoutFlag() {
+		t.Errorf("hasTimeoutFlag: got true, want false")
+	}
+	// the timeout flag defined by Flags is left to the command
+	if err := cmd.run("app", []string{"--timeout", "soon"}); err != nil {
+		t.Fatal(err)
+	}
+	if timeout != "soon" {
+		t.Errorf("timeout: got %q, want %q", timeout, "soon")
+	}
+}
+
+func TestCommand_Timeout_parseExec(t *testing.T) {
+	called := false
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get": {
+				Timeout: time.Second,
+				ParseExec: func(fullname string, arguments []string) error {
+					called = true
+					return nil
+				},
+			},
+		},
+	}
+
+	err := app.run("app", []string{"get"})
+	if !errors.Is(err, ErrTimeoutNoContext) {
+		t.Fatalf("error: got %v, want ErrTimeoutNoContext", err)
+	}
+	if want := "app get: timeout requires ParseExecContext"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if called {
+		t.Errorf("ParseExec called, want not called")
+	}
+}
+
+func TestCommand_Timeout_help(t *testing.T) {
+	cmd := &Command{
+		Timeout:          30 * time.Second,
+		ParseExecContext: waitDone,
+		Flags: func(fs *flag.FlagSet) {
+			AliasedIntVar(fs, new(int), "workers,w", 1, "number of workers")
+		},
+	}
+	var buf strings.Builder
+	if err := PrintHelp(&buf, "app", cmd); err != nil {
+		t.Fatal(err)
+	}
+	want := `Options:
+        --timeout duration  maximum duration of the command (default 30s)
+    -w, --workers int       number of workers (default 1)
+`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("PrintHelp: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+// withSignals redefines the signal functions to send the signals to the returned channel.
+func withSignals(t *testing.T) <-chan chan<- os.Signal {
+	t.Helper()
+	oldNotify, oldStop := signalNotify, signalStop
+	t.Cleanup(func() { signalNotify, signalStop = oldNotify, oldStop })
+
+	chans := make(chan chan<- os.Signal, 1)
+	signalNotify = func(c chan<- os.Signal, sig ...os.Signal) { chans <- c }
+	signalStop = func(c chan<- os.Signal) {}
+	return chans
+}
+
+func TestRunContext_interrupt(t *testing.T) {
+	chans := withSignals(t)
+	app := &Command{
+		ParseExecContext: func(ctx context.Context, fullname string, arguments []string) error {
+			(<-chans) <- os.Interrupt
+			return waitDone(ctx, fullname, arguments)
+		},
+	}
+	err := app.runContext(context.Background(), "app", nil, true)
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("error: got %v, want ErrInterrupted", err)
+	}
+	if want := "app: interrupted"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if ExitCode(err) != ExitInterrupted {
+		t.Errorf("ExitCode = %d, want %d", ExitCode(err), ExitInterrupted)
+	}
+}
+
+func TestRunContext_forceExit(t *testing.T) {
+	chans := withSignals(t)
+	oldExit := exit
+	defer func() { exit = oldExit }()
+	exited := make(chan int, 1)
+	exit = func(code int) { exited <- code }
+
+	app := &Command{
+		ParseExecContext: func(ctx context.Context, fullname string, arguments []string) error {
+			c := <-chans
+			c <- os.Interrupt
+			<-ctx.Done()
+			// the command does not terminate after the cancellation
+			c <- os.Interrupt
+			if code := <-exited; code != ExitInterrupted {
+				t.Errorf("exit code: got %d, want %d", code, ExitInterrupted)
+			}
+			return nil
+		},
+	}
+	if err := app.runContext(context.Background(), "app", nil, true); err != nil {
+		t.Errorf("error: got %v, want nil", err)
+	}
+}
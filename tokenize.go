@@ -0,0 +1,266 @@
+package flagx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// token is an argument found by tokenize, with its position in the source.
+type token struct {
+	value string // unquoted value of the argument
+	line  int    // line of the first character of the argument, starting from 1
+	col   int    // column of the first character of the argument, starting from 1
+}
+
+// SyntaxError is the error returned by Split for a malformed string,
+// with the position of the error.
+type SyntaxError struct {
+	Line int    // line of the error, starting from 1
+	Col  int    // column of the error, starting from 1
+	Msg  string // description of the error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Msg)
+}
+
+// msgTrailingBackslash is the message of the error of an unquoted
+// backslash at the end of the string.
+const msgTrailingBackslash = "trailing backslash"
+
+// Split splits s into arguments using the POSIX shell quoting rules:
+//   - arguments are separated by unquoted white spaces (including newlines);
+//   - an unquoted '#' at the beginning of an argument starts a comment
+//     that ends at the end of the line;
+//   - characters enclosed in single quotes are preserved literally;
+//   - characters enclosed in double quotes are preserved literally,
+//     except backslash that escapes '"', '\', '$', '`' and newline;
+//   - an unquoted backslash preserves the literal value of the next character,
+//     except backslash-newline that is a line continuation and is removed.
+//
+// Adjacent quoted and unquoted parts are joined in a single argument,
+// and an empty quoted string is an empty argument. For example
+//
+//	get -n 'isin 1' "isin\"2" isin\ 3
+//
+// is split into "get", "-n", "isin 1", "isin\"2" and "isin 3".
+// A malformed string, for example with an unterminated quote,
+// returns a *SyntaxError.
+func Split(s string) ([]string, error) {
+	return SplitExpand(s, nil)
+}
+
+// SplitExpand is Split, with the variables expanded by mapping, if not nil:
+// "$NAME" and "${NAME}", unquoted or enclosed in double quotes, are replaced
+// by mapping(NAME), as in the shell; for example SplitExpand(s, os.Getenv)
+// expands the environment variables. Unlike the shell, the value of a variable
+// is not split into arguments. An unquoted variable with empty value is not
+// an argument, and a '$' not followed by a name is preserved literally.
+func SplitExpand(s string, mapping func(string) string) ([]string, error) {
+	tokens, err := tokenizeExpand(s, mapping)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, len(tokens))
+	for j, t := range tokens {
+		args[j] = t.value
+	}
+	return args, nil
+}
+
+// tokenize splits s into arguments, as Split,
+// returning the position of each argument too.
+func tokenize(s string) ([]token, error) {
+	return tokenizeExpand(s, nil)
+}
+
+// tokenizeExpand is tokenize, with the variables expanded by mapping,
+// if not nil (see SplitExpand).
+func tokenizeExpand(s string, mapping func(string) string) ([]token, error) {
+	var (
+		tokens []token
+		buf    strings.Builder
+		cur    *token // argument being read, or nil
+	)
+
+	line, col := 1, 0
+	rs := []rune(s)
+
+	// begin starts a new argument at the current position, if not already started.
+	begin := func() {
+		if cur == nil {
+			cur = &token{line: line, col: col}
+		}
+	}
+	// variable returns the name of the variable at rs[i] == '$',
+	// and the index of its last character. It returns ok false
+	// if there is no variable at rs[i].
+	variable := func(i int) (name string, last int, ok bool, err error) {
+		if mapping == nil || i+1 == len(rs) {
+			return "", i, false, nil
+		}
+		if rs[i+1] == '{' {
+			j := i + 2
+			for j < len(rs) && isVarRune(rs[j], j == i+2) {
+				j++
+			}
+			if j == len(rs) || rs[j] != '}' || j == i+2 {
+				return "", i, false, &SyntaxError{line, col, "bad variable substitution"}
+			}
+			return string(rs[i+2 : j]), j, true, nil
+		}
+		j := i + 1
+		for j < len(rs) && isVarRune(rs[j], j == i+1) {
+			j++
+		}
+		if j == i+1 {
+			return "", i, false, nil
+		}
+		return string(rs[i+1 : j]), j - 1, true, nil
+	}
+
+	// end terminates the current argument, if any.
+	end := func() {
+		if cur != nil {
+			cur.value = buf.String()
+			tokens = append(tokens, *cur)
+			cur = nil
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		col++
+
+		switch {
+		case r == '\n':
+			end()
+			line, col = line+1, 0
+
+		case r == ' ' || r == '\t' || r == '\r':
+			end()
+
+		case r == '#' && cur == nil:
+			for i+1 < len(rs) && rs[i+1] != '\n' {
+				i++
+			}
+
+		case r == '\\':
+			if i+1 == len(rs) {
+				return nil, &SyntaxError{line, col, msgTrailingBackslash}
+			}
+			i++
+			if rs[i] == '\n' {
+				// line continuation: it does not start an argument
+				line, col = line+1, 0
+				continue
+			}
+			begin()
+			col++
+			buf.WriteRune(rs[i])
+
+		case r == '\'':
+			begin()
+			qline, qcol := line, col
+			for {
+				i++
+				if i == len(rs) {
+					return nil, &SyntaxError{qline, qcol, "unterminated single quote"}
+				}
+				col++
+				if rs[i] == '\'' {
+					break
+				}
+				if rs[i] == '\n' {
+					line, col = line+1, 0
+				}
+				buf.WriteRune(rs[i])
+			}
+
+		case r == '"':
+			begin()
+			qline, qcol := line, col
+			for {
+				i++
+				if i == len(rs) {
+					return nil, &SyntaxError{qline, qcol, "unterminated double quote"}
+				}
+				col++
+				c := rs[i]
+				if c == '"' {
+					break
+				}
+				if c == '\\' && i+1 < len(rs) && strings.ContainsRune("\"\\$`\n", rs[i+1]) {
+					i++
+					col++
+					c = rs[i]
+					if c == '\n' {
+						line, col = line+1, 0
+						continue
+					}
+				} else if c == '\n' {
+					line, col = line+1, 0
+				} else if c == '$' {
+					name, last, ok, err := variable(i)
+					if err != nil {
+						return nil, err
+					}
+					if ok {
+						col += last - i
+						i = last
+						buf.WriteString(mapping(name))
+						continue
+					}
+				}
+				buf.WriteRune(c)
+			}
+
+		case r == '$' && mapping != nil:
+			name, last, ok, err := variable(i)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				begin()
+				buf.WriteRune(r)
+				break
+			}
+			startCol := col
+			col += last - i
+			i = last
+			if v := mapping(name); v != "" {
+				if cur == nil {
+					cur = &token{line: line, col: startCol}
+				}
+				buf.WriteString(v)
+			}
+
+		default:
+			begin()
+			buf.WriteRune(r)
+		}
+	}
+	end()
+
+	return tokens, nil
+}
+
+// continuesLine checks if the line s ends with an unquoted backslash,
+// that continues it on the next line. An escaped backslash (`\\`)
+// or a backslash enclosed in single quotes does not continue the line.
+func continuesLine(s string) bool {
+	if !strings.HasSuffix(s, `\`) {
+		return false
+	}
+	_, err := tokenize(s)
+	var se *SyntaxError
+	return errors.As(err, &se) && se.Msg == msgTrailingBackslash
+}
+
+// isVarRune checks if r can be part of the name of a variable:
+// a letter, a digit (not first) or '_'.
+func isVarRune(r rune, first bool) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (!first && r >= '0' && r <= '9')
+}
@@ -0,0 +1,210 @@
+package flagx
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_tokenize(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		want       []string
+		wantErrMsg string
+	}{
+		{
+			name: "empty",
+			s:    "",
+			want: []string{},
+		},
+		{
+			name: "white spaces",
+			s:    "  a\tb \n c\r\n",
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "comments",
+			s:    "# comment\na # comment b\nc#d",
+			want: []string{"a", "c#d"},
+		},
+		{
+			name: "single quotes",
+			s:    `'a b' 'c\d' 'e"f'`,
+			want: []string{"a b", `c\d`, `e"f`},
+		},
+		{
+			name: "double quotes",
+			s:    `"a b" "c\"d" "e\f" "$\$"`,
+			want: []string{"a b", `c"d`, `e\f`, "$$"},
+		},
+		{
+			name: "backslash",
+			s:    `a\ b c\\d \#e`,
+			want: []string{"a b", `c\d`, "#e"},
+		},
+		{
+			name: "line continuation",
+			s:    "a\\\nb c",
+			want: []string{"ab", "c"},
+		},
+		{
+			name: "line continuation after space",
+			s:    "a \\\n  b",
+			want: []string{"a", "b"},
+		},
+		{
+			name: "joined parts",
+			s:    `--mode="A B"'C'`,
+			want: []string{"--mode=A BC"},
+		},
+		{
+			name: "empty quoted",
+			s:    `a "" ''`,
+			want: []string{"a", "", ""},
+		},
+		{
+			name:       "unterminated single quote",
+			s:          "a\n  'b",
+			wantErrMsg: "2:3: unterminated single quote",
+		},
+		{
+			name:       "unterminated double quote",
+			s:          `"a`,
+			wantErrMsg: "1:1: unterminated double quote",
+		},
+		{
+			name:       "trailing backslash",
+			s:          `a\`,
+			wantErrMsg: "1:2: trailing backslash",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenize(tt.s)
+			if tt.wantErrMsg != "" {
+				if err == nil || err.Error() != tt.wantErrMsg {
+					t.Errorf("tokenize() error = %v, wantErrMsg %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tokenize() error = %v, want nil", err)
+			}
+			got := []string{}
+			for _, tok := range tokens {
+				got = append(got, tok.value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tokenize_position(t *testing.T) {
+	tokens, err := tokenize("a\n  'b c'\n\n   d")
+	if err != nil {
+		t.Fatalf("tokenize() error = %v, want nil", err)
+	}
+	want := []token{
+		{"a", 1, 1},
+		{"b c", 2, 3},
+		{"d", 4, 4},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("tokenize() = %v, want %v", tokens, want)
+	}
+}
+
+func Test_tokenizeExpand(t *testing.T) {
+	env := map[string]string{
+		"ISIN":  "IT0001",
+		"EMPTY": "",
+		"SPACE": "a b",
+		"_X1":   "x",
+	}
+	mapping := func(name string) string { return env[name] }
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr string
+	}{
+		{"unquoted", "get $ISIN ${ISIN}-2", []string{"get", "IT0001", "IT0001-2"}, ""},
+		{"double quoted", `"isin=$ISIN" "${SPACE}"`, []string{"isin=IT0001", "a b"}, ""},
+		{"single quoted", `'$ISIN'`, []string{"$ISIN"}, ""},
+		{"escaped", `\$ISIN "\$ISIN"`, []string{"$ISIN", "$ISIN"}, ""},
+		{"not split", "$SPACE", []string{"a b"}, ""},
+		{"empty unquoted", "a $EMPTY b", []string{"a", "b"}, ""},
+		{"empty quoted", `a "$EMPTY" b`, []string{"a", "", "b"}, ""},
+		{"undefined", "a$UNDEFINED-b", []string{"a-b"}, ""},
+		{"name chars", "$_X1.$1 $", []string{"x.$1", "$"}, ""},
+		{"bad substitution", "a ${ISIN", nil, "1:3: bad variable substitution"},
+		{"empty substitution", `"${}"`, nil, "1:2: bad variable substitution"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenizeExpand(tt.s, mapping)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("tokenizeExpand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tokenizeExpand() error = %v, want nil", err)
+			}
+			got := []string{}
+			for _, tok := range tokens {
+				got = append(got, tok.value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeExpand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// without mapping, the variables are not expanded
+	tokens, err := tokenize("$ISIN")
+	if err != nil || len(tokens) != 1 || tokens[0].value != "$ISIN" {
+		t.Errorf("tokenize() = %v, %v", tokens, err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got, err := Split(`get -n 'isin 1' "isin\"2" isin\ 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"get", "-n", "isin 1", `isin"2`, "isin 3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() = %q, want %q", got, want)
+	}
+
+	got, err = Split("")
+	if err != nil || len(got) != 0 {
+		t.Errorf("Split(\"\") = %q, %v, want []", got, err)
+	}
+
+	_, err = Split("get\n  \"isin")
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("Split() error = %v, want *SyntaxError", err)
+	}
+	if se.Line != 2 || se.Col != 3 || se.Msg != "unterminated double quote" {
+		t.Errorf("SyntaxError = %+v", se)
+	}
+}
+
+func TestSplitExpand(t *testing.T) {
+	t.Setenv("FLAGX_ISIN", "IT0001")
+	got, err := SplitExpand(`get $FLAGX_ISIN "${FLAGX_ISIN}-2" '$FLAGX_ISIN'`, os.Getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"get", "IT0001", "IT0001-2", "$FLAGX_ISIN"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitExpand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// helpCommandName is the name of the help command added by HelpCommand.
+const helpCommandName = "help"
+
+// Topic is a help topic that is not a command,
+// for example the description of the environment variables.
+type Topic struct {
+	Short string // one-line description, shown in the list of topics
+	Long  string // text of the topic, printed by "help <topic>"
+}
+
+// namedTopic is a help topic, with its names.
+type namedTopic struct {
+	names []string // primary name followed by the aliases
+	topic *Topic
+}
+
+// topics returns the help topics of the command sorted by primary name.
+// The topics without a valid name are ignored.
+func (cmd *Command) topics() []*namedTopic {
+	res := []*namedTopic{}
+	for key, t := range cmd.Topics {
+		if ns := splitTrimSpace(key, ","); len(ns) > 0 {
+			res = append(res, &namedTopic{ns, t})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].names[0] < res[j].names[0] })
+	return res
+}
+
+// helpCommand returns the "help" sub-command of the command cmd,
+// with full name fullname.
+// A help argument ("-h", "-help" or "--help") shows the help of the help command.
+func (cmd *Command) helpCommand(fullname string) *Command {
+	hc := &Command{
+		Short:       "Show the help of a command or topic",
+		Positionals: []*Positional{{Name: "[command...|topic]", Usage: "path of sub-command names, or help topic"}},
+	}
+	hc.ParseExec = func(name string, arguments []string) error {
+		if len(arguments) > 0 && isHelpArg(arguments[0]) {
+			return hc.showHelp(name, nil)
+		}
+		return cmd.printHelpPath(fullname, arguments)
+	}
+	return hc
+}
+
+// hasSubCmdName checks if the command has a sub-command with the given name.
+func (cmd *Command) hasSubCmdName(name string) bool {
+	for key := range cmd.SubCmd {
+		if contains(splitTrimSpace(key, ","), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// printHelpPath prints, to the output of flag.CommandLine, the help of the
+// command found following the path of sub-command names from cmd, or the
+// help topic of cmd named path[0]. It returns a *HelpError if the help is printed.
+func (cmd *Command) printHelpPath(fullname string, path []string) error {
+	out := flag.CommandLine.Output()
+
+	if len(path) == 1 {
+		for _, t := range cmd.topics() {
+			if contains(t.names, path[0]) && !cmd.hasSubCmdName(path[0]) {
+				text := t.topic.Long
+				if text == "" {
+					text = t.topic.Short
+				}
+				fmt.Fprintln(out, strings.TrimRight(text, "\n"))
+				return &HelpError{Path: fullname + " " + t.names[0]}
+			}
+		}
+	}
+
+	var names []string
+	for _, name := range path {
+		scs, err := cmd.subCommands(fullname)
+		if err != nil {
+			return err
+		}
+		var found *subCommand
+		for _, sc := range scs {
+			if contains(sc.names, name) {
+				found = sc
+				break
+			}
+		}
+		if found == nil {
+			if len(path) == 1 && len(cmd.Topics) > 0 {
+				return newError(ErrTopicNotFound, fullname, name)
+			}
+			return newError(ErrCommandNotFound, fullname, name)
+		}
+		cmd, fullname, names = found.cmd, fullname+" "+found.primary(), found.visibleNames()
+	}
+
+	return cmd.showHelp(fullname, names)
+}
@@ -0,0 +1,161 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestCommand_HelpCommand(t *testing.T) {
+	var (
+		config  string
+		workers int
+	)
+
+	app := &Command{
+		Short:       "Manage the quotes",
+		HelpCommand: true,
+		SubCmd: map[string]*Command{
+			"get,g": {
+				Short: "Get the quotes",
+				Flags: func(fs *flag.FlagSet) {
+					AliasedStringVar(fs, &config, "config,c", "", "config file")
+					AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+				},
+				SubCmd: map[string]*Command{
+					"isins,i": {Short: "Get the quotes of the isins"},
+				},
+			},
+			"tor,t": {
+				Short:  "Checks if Tor network will be used",
+				Hidden: true,
+			},
+		},
+		Topics: map[string]*Topic{
+			"environment,env": {
+				Short: "Environment variables",
+				Long:  "HTTP_PROXY, HTTPS_PROXY and NOPROXY environment variables.\n",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		wantOutput string
+		wantErr    error
+	}{
+		{
+			name: "help",
+			args: "help",
+			wantOutput: `Usage:
+    app <command> [options]
+
+Manage the quotes
+
+Available commands:
+    get   Get the quotes
+    help  Show the help of a command or topic
+
+Help topics:
+    environment  Environment variables
+`,
+		},
+		{
+			name: "help command",
+			args: "help get",
+			wantOutput: `Usage:
+    app get <command> [options]
+
+Aliases: g
+
+Get the quotes
+
+Available commands:
+    isins  Get the quotes of the isins
+
+Options:
+    -c, --config  string  config file
+    -w, --workers int     number of workers (default 1)
+`,
+		},
+		{
+			name:       "help alias",
+			args:       "help g",
+			wantOutput: "app get <command> [options]",
+		},
+		{
+			name:       "help sub-command",
+			args:       "help g i",
+			wantOutput: "app get isins [options]\n\nAliases: i\n\nGet the quotes of the isins\n",
+		},
+		{
+			name:       "help hidden command",
+			args:       "help tor",
+			wantOutput: "app tor [options]",
+		},
+		{
+			name:       "help topic",
+			args:       "help environment",
+			wantOutput: "HTTP_PROXY, HTTPS_PROXY and NOPROXY environment variables.\n",
+		},
+		{
+			name:       "help topic alias",
+			args:       "help env",
+			wantOutput: "HTTP_PROXY, HTTPS_PROXY and NOPROXY environment variables.\n",
+		},
+		{
+			name:    "help unknown topic",
+			args:    "help unknown",
+			wantErr: ErrTopicNotFound,
+		},
+		{
+			name:    "help unknown sub-command",
+			args:    "help get unknown",
+			wantErr: ErrCommandNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			flag.CommandLine.SetOutput(&out)
+
+			err := app.handleSubCmd("app", splitTrimSpace(tt.args, " "))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error: got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if !errors.Is(err, flag.ErrHelp) {
+				t.Fatalf("error: got %v, want flag.ErrHelp", err)
+			}
+			if got := out.String(); !strings.Contains(got, tt.wantOutput) {
+				t.Errorf("output: got\n%s\nwant\n%s", got, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestCommand_HelpCommand_Defined(t *testing.T) {
+	called := false
+	app := &Command{
+		HelpCommand: true,
+		SubCmd: map[string]*Command{
+			"help,h": {
+				ParseExec: func(name string, arguments []string) error {
+					called = true
+					return nil
+				},
+			},
+		},
+	}
+	if err := app.handleSubCmd("app", []string{"help"}); err != nil {
+		t.Fatalf("error: got %q, want nil", err)
+	}
+	if !called {
+		t.Errorf("the defined help command was not called")
+	}
+}
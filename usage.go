@@ -0,0 +1,243 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// flagDef describes an aliased flag of a flag set:
+// the primary flag and all its aliases.
+type flagDef struct {
+	flag      *flag.Flag // primary flag
+	names     []string   // primary name followed by the aliases
+	typ       string     // name of the type of the flag value; empty for boolean flags
+	usage     string     // usage string of the primary flag
+	negatable bool       // the "no-<name>" flags are defined
+	info      *flagInfo  // flagx attributes of the flag, or nil
+}
+
+// hidden checks if the flag is hidden.
+func (d *flagDef) hidden() bool {
+	return d.info != nil && d.info.hidden
+}
+
+// env returns the environment variable bound to the flag, if any.
+func (d *flagDef) env() string {
+	if d.info == nil {
+		return ""
+	}
+	return d.info.env
+}
+
+// visibleNames returns the names of the flag, without the hidden aliases.
+// The primary name is always returned.
+func (d *flagDef) visibleNames() []string {
+	res := []string{d.names[0]}
+	for _, n := range d.names[1:] {
+		if d.info == nil || !d.info.isHiddenName(n) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// visibleFlagDefs returns the flag definitions that are not hidden.
+func visibleFlagDefs(defs []*flagDef) []*flagDef {
+	res := []*flagDef{}
+	for _, d := range defs {
+		if !d.hidden() {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// shortNames returns the one character visible names of the flag.
+func (d *flagDef) shortNames() []string {
+	res := []string{}
+	for _, n := range d.visibleNames() {
+		if len(n) == 1 {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// longNames returns the visible names of the flag with more than one character.
+func (d *flagDef) longNames() []string {
+	res := []string{}
+	for _, n := range d.visibleNames() {
+		if len(n) > 1 {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// defaultValue returns the default value of the flag,
+// or the empty string if it is the zero value of its type.
+func (d *flagDef) defaultValue() string {
+	dv := d.flag.DefValue
+	if isZeroDefault(dv) {
+		return ""
+	}
+	if d.typ == "string" {
+		return fmt.Sprintf("%q", dv)
+	}
+	return dv
+}
+
+// isZeroDefault checks if the default value dv is the zero value
+// of the common flag types, that is not shown in the usage.
+func isZeroDefault(dv string) bool {
+	switch dv {
+	case "", "0", "false", "[]":
+		return true
+	}
+	return false
+}
+
+// flagDefs groups the flags of the flag set by aliases.
+// The result is sorted by primary name, as flag.VisitAll visits the flags
+// in lexicographical order.
+func flagDefs(fs *flag.FlagSet) []*flagDef {
+	defs := []*flagDef{}
+	aliases := map[string][]string{}
+	negated := map[string]bool{}
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if nv, ok := f.Value.(*negatedValue); ok {
+			negated[nv.name] = true
+			return
+		}
+		if p := primaryName(fs, f); p != f.Name {
+			aliases[p] = append(aliases[p], f.Name)
+			return
+		}
+		typ, usage := flag.UnquoteUsage(&flag.Flag{Usage: f.Usage, Value: unwrapValue(f.Value)})
+		switch unwrapValue(f.Value).(type) {
+		case *astring:
+			typ = "strings"
+		case *SecretString:
+			typ = "string"
+		}
+		d := &flagDef{
+			flag:  f,
+			names: []string{f.Name},
+			typ:   typ,
+			usage: usage,
+		}
+		if ov, ok := f.Value.(*optValue); ok {
+			d.info = ov.info
+		}
+		defs = append(defs, d)
+	})
+
+	for _, d := range defs {
+		d.names = append(d.names, aliases[d.flag.Name]...)
+		d.negatable = negated[d.flag.Name]
+	}
+	return defs
+}
+
+// primaryName returns the primary name of the flag f.
+// A flag whose usage string is the alias usage of an existing flag
+// is an alias of that flag.
+func primaryName(fs *flag.FlagSet, f *flag.Flag) string {
+	if strings.HasPrefix(f.Usage, `alias of "`) && strings.HasSuffix(f.Usage, `"`) {
+		p := f.Usage[len(`alias of "`) : len(f.Usage)-1]
+		if fs.Lookup(p) != nil {
+			return p
+		}
+	}
+	return f.Name
+}
+
+// namesColumn returns the names of the flag as shown in the usage message.
+// One character names are prefixed by "-" and shown first,
+// the other names are prefixed by "--".
+// If pad is true and the flag has no one character name,
+// the names are indented to be aligned with the long names of the other flags.
+func (d *flagDef) namesColumn(pad bool) string {
+	names := []string{}
+	for _, n := range d.shortNames() {
+		names = append(names, "-"+n)
+	}
+	for _, n := range d.longNames() {
+		if d.negatable {
+			names = append(names, "--[no-]"+n)
+		} else {
+			names = append(names, "--"+n)
+		}
+	}
+	s := strings.Join(names, ", ")
+	if pad && len(d.shortNames()) == 0 {
+		s = "    " + s
+	}
+	return s
+}
+
+// PrintDefaults prints, to the output of the flag set, the default values
+// of all defined flags, in a GNU-like style. The aliases of a flag are
+// shown on the same line of the primary name. The hidden flags and aliases
+// are not shown. The usage strings are aligned in a column and wrapped
+// at the width of the help (see HelpWidth). For example:
+//
+//	-c, --config       string  config file
+//	-n, --[no-]dry-run         perform a trial run
+//	-w, --workers      int     number of workers (default 1)
+func PrintDefaults(fs *flag.FlagSet) {
+	w := fs.Output()
+	printDefaults(w, visibleFlagDefs(flagDefs(fs)), helpWidth(w), styler(useColor(w)))
+}
+
+// printDefaults prints the usage of the flag definitions to w.
+// The usage strings are wrapped at width characters, aligned in a column.
+// The flag names and the default values are styled by st.
+func printDefaults(w io.Writer, defs []*flagDef, width int, st styler) {
+	pad := false
+	for _, d := range defs {
+		if len(d.shortNames()) > 0 {
+			pad = true
+			break
+		}
+	}
+
+	nw, tw := 0, 0
+	for _, d := range defs {
+		if l := len(d.namesColumn(pad)); l > nw {
+			nw = l
+		}
+		if l := len(d.typ); l > tw {
+			tw = l
+		}
+	}
+
+	for _, d := range defs {
+		names := d.namesColumn(pad)
+		trimmed := strings.TrimLeft(names, " ")
+		names = names[:len(names)-len(trimmed)] + st.flagName(trimmed) + strings.Repeat(" ", nw-len(names))
+		col := 4 + nw
+		line := "    " + names
+		if tw > 0 {
+			line += fmt.Sprintf(" %-*s", tw, d.typ)
+			col += 1 + tw
+		}
+		usage := d.usage
+		var suffix string
+		if dv := d.defaultValue(); dv != "" {
+			suffix += " (default " + dv + ")"
+		}
+		if env := d.env(); env != "" {
+			suffix += " [$" + env + "]"
+		}
+		text := wrapText(usage+suffix, col+2, width)
+		if suffix != "" && bool(st) && strings.HasSuffix(text, suffix) {
+			// style the suffix, if not split by the wrapping
+			text = text[:len(text)-len(suffix)] + st.dim(suffix)
+		}
+		fmt.Fprintf(w, "%s  %s\n", line, text)
+	}
+}
@@ -0,0 +1,39 @@
+package flagx
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_PrintDefaults(t *testing.T) {
+	var (
+		config     string
+		configType string
+		dryrun     bool
+		workers    int
+		isins      []string
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	AliasedStringVar(fs, &config, "config,c", "", "config file")
+	AliasedStringVar(fs, &configType, "config-type", "yaml", "config type")
+	AliasedBoolVar(fs, &dryrun, "dry-run,n", false, "perform a trial run", Negatable())
+	AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+	AliasedStringsVar(fs, &isins, "isins,i", "list of isins\nto get the quotes")
+
+	var buf strings.Builder
+	fs.SetOutput(&buf)
+	PrintDefaults(fs)
+
+	want := `    -c, --config       string   config file
+        --config-type  string   config type (default "yaml")
+    -n, --[no-]dry-run          perform a trial run
+    -i, --isins        strings  list of isins
+                                to get the quotes
+    -w, --workers      int      number of workers (default 1)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("PrintDefaults(): got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package flagx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// flagx user aliases errors
+var (
+	ErrInvalidAlias = errors.New("invalid user alias")
+	ErrAliasLoop    = errors.New("user alias loop")
+)
+
+// ReadUserAliases reads the user aliases from r, in the format of a
+// configuration file with one "name = expansion" alias per line,
+// for example:
+//
+//	# quotes of the isins, with 8 workers and no updates
+//	q = get -n -w 8
+//
+// Blank lines and lines beginning with '#' are ignored.
+// The expansion is split into arguments with shell-like rules,
+// as the response files (see ExpandResponseFiles).
+func ReadUserAliases(r io.Reader) (map[string]string, error) {
+	aliases := map[string]string{}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || s[0] == '#' {
+			continue
+		}
+		name, expansion, err := parseUserAlias(s)
+		if err != nil {
+			return nil, wrapErrorf(ErrInvalidAlias, "%d: %s: %v", line, ErrInvalidAlias, err)
+		}
+		aliases[name] = expansion
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// LoadUserAliases reads the user aliases from the configuration file
+// at path (see ReadUserAliases). The errors are prefixed by the path.
+func LoadUserAliases(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	aliases, err := ReadUserAliases(f)
+	if err != nil {
+		return nil, wrapErrorf(err, "%s:%s", path, err)
+	}
+	return aliases, nil
+}
+
+// parseUserAlias parses the "name = expansion" line s.
+func parseUserAlias(s string) (name, expansion string, err error) {
+	j := strings.Index(s, "=")
+	if j < 0 {
+		return "", "", errors.New(`missing "="`)
+	}
+	name, expansion = strings.TrimSpace(s[:j]), strings.TrimSpace(s[j+1:])
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t,") {
+		return "", "", fmt.Errorf("invalid name %q", name)
+	}
+	if _, err := userAliasArgs(expansion); err != nil {
+		return "", "", err
+	}
+	return name, expansion, nil
+}
+
+// userAliasArgs returns the arguments of the expansion of a user alias.
+func userAliasArgs(expansion string) ([]string, error) {
+	args, err := Split(expansion)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty expansion")
+	}
+	return args, nil
+}
+
+// expandUserAlias dispatches the arguments to the command cmd, with full name
+// fullname, after replacing the user alias name by its expansion.
+func (cmd *Command) expandUserAlias(fullname string, ds *dispatchState, name string, arguments []string) error {
+	key := fullname + " " + name
+	if ds.expanded[key] {
+		return newError(ErrAliasLoop, fullname, name)
+	}
+	args, err := userAliasArgs(cmd.UserAliases[name])
+	if err != nil {
+		return &Error{Path: fullname, Token: name, Kind: ErrInvalidAlias, Err: err,
+			msg: fmt.Sprintf("%s: %s %q: %v", fullname, ErrInvalidAlias, name, err)}
+	}
+	if ds.expanded == nil {
+		ds.expanded = map[string]bool{}
+	}
+	ds.expanded[key] = true
+	return cmd.dispatch(fullname, ds, append(args, arguments...))
+}
+
+// userAlias is a user alias with its name.
+type userAlias struct {
+	name      string
+	expansion string
+}
+
+// activeUserAliases returns the user aliases of the command that are not
+// shadowed by the sub-commands scs, sorted by name.
+func (cmd *Command) activeUserAliases(scs []*subCommand) []*userAlias {
+	res := []*userAlias{}
+	for name, expansion := range cmd.UserAliases {
+		shadowed := false
+		for _, sc := range scs {
+			if contains(sc.names, name) {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			res = append(res, &userAlias{name, expansion})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
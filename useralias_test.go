@@ -0,0 +1,188 @@
+package flagx
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadUserAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "aliases",
+			input: `# shortcuts
+q = get -n -w 8
+
+src=sources
+  t = get --proxy 'socks5://127.0.0.1:9050'  
+`,
+			want: map[string]string{
+				"q":   "get -n -w 8",
+				"src": "sources",
+				"t":   "get --proxy 'socks5://127.0.0.1:9050'",
+			},
+		},
+		{
+			name:    "missing equal",
+			input:   "q get",
+			wantErr: `1: invalid user alias: missing "="`,
+		},
+		{
+			name:    "invalid name",
+			input:   "\n-q = get",
+			wantErr: `2: invalid user alias: invalid name "-q"`,
+		},
+		{
+			name:    "empty expansion",
+			input:   "q =",
+			wantErr: "1: invalid user alias: empty expansion",
+		},
+		{
+			name:    "unterminated quote",
+			input:   "q = get 'isin",
+			wantErr: "1: invalid user alias: 1:5: unterminated single quote",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadUserAliases(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidAlias) {
+					t.Fatalf("error: got %v, want ErrInvalidAlias", err)
+				}
+				if err.Error() != tt.wantErr {
+					t.Errorf("error: got %q, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadUserAliases(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"aliases": "q = get -n\n",
+		"invalid": "q\n",
+	})
+
+	got, err := LoadUserAliases(filepath.Join(dir, "aliases"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"q": "get -n"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	path := filepath.Join(dir, "invalid")
+	_, err = LoadUserAliases(path)
+	if want := path + `:1: invalid user alias: missing "="`; err == nil || err.Error() != want {
+		t.Errorf("error: got %v, want %q", err, want)
+	}
+}
+
+func TestCommand_UserAliases(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	exec := func(name string, arguments []string) error {
+		gotName, gotArgs = name, arguments
+		return nil
+	}
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get,g":   {ParseExec: exec},
+			"sources": {ParseExec: exec},
+		},
+		UserAliases: map[string]string{
+			"q":       "get -n -w 8",
+			"qq":      "q --proxy 'socks5://127.0.0.1:9050'",
+			"g":       "sources",
+			"loop":    "loop2 -n",
+			"loop2":   "loop",
+			"invalid": "get 'isin",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantArgs []string
+		wantErr  error
+	}{
+		{"alias", []string{"q", "isin1"}, "app get", []string{"-n", "-w", "8", "isin1"}, nil},
+		{"nested", []string{"qq"}, "app get", []string{"-n", "-w", "8", "--proxy", "socks5://127.0.0.1:9050"}, nil},
+		{"no shadowing", []string{"g", "-x"}, "app get", []string{"-x"}, nil},
+		{"loop", []string{"loop"}, "", nil, ErrAliasLoop},
+		{"invalid", []string{"invalid"}, "", nil, ErrInvalidAlias},
+		{"not found", []string{"qqq"}, "", nil, ErrCommandNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotArgs = "", nil
+			err := app.run("app", tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error: got %v, want %v", err, tt.wantErr)
+				}
+				if ExitCode(err) != ExitUsage {
+					t.Errorf("ExitCode = %d, want %d", ExitCode(err), ExitUsage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotName != tt.wantName || !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("got %q %q, want %q %q", gotName, gotArgs, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+
+	err := app.run("app", []string{"loop"})
+	if want := `app: user alias loop "loop"`; err == nil || err.Error() != want {
+		t.Errorf("error: got %v, want %q", err, want)
+	}
+}
+
+func TestPrintHelp_UserAliases(t *testing.T) {
+	app := &Command{
+		SubCmd: map[string]*Command{
+			"get,g": {Short: "Get the quotes"},
+		},
+		UserAliases: map[string]string{
+			"q":  "get -n -w 8",
+			"g":  "sources",
+			"tq": "get --proxy socks5://127.0.0.1:9050",
+		},
+	}
+	var buf strings.Builder
+	if err := PrintHelp(&buf, "app", app); err != nil {
+		t.Fatal(err)
+	}
+	want := `Usage:
+    app <command> [options]
+
+Available commands:
+    get  Get the quotes
+
+User aliases:
+    q   get -n -w 8
+    tq  get --proxy socks5://127.0.0.1:9050
+`
+	if got := buf.String(); got != want {
+		t.Errorf("PrintHelp: got\n%s\nwant\n%s", got, want)
+	}
+}
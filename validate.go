@@ -0,0 +1,80 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// flagx flag validation errors
+var (
+	ErrRequiredFlag = errors.New("required flag not passed")
+	ErrInvalidEnum  = errors.New("invalid value")
+)
+
+// Required option marks the flag as required: CheckRequired returns an error
+// if the flag is not passed in the command line, nor set by its environment variable.
+func Required() FlagOption {
+	return func(fi *flagInfo) {
+		fi.required = true
+	}
+}
+
+// Enum option restricts the accepted values of the flag to the given values.
+// Each item of a strings flag must be one of the values.
+func Enum(values ...string) FlagOption {
+	return func(fi *flagInfo) {
+		fi.enum = append(fi.enum, values...)
+	}
+}
+
+// checkEnum checks that the value s is one of the accepted values of the flag.
+func (fi *flagInfo) checkEnum(v flag.Value, s string) error {
+	if len(fi.enum) == 0 {
+		return nil
+	}
+	items := []string{s}
+	if _, isList := v.(*astring); isList {
+		items = splitTrimSpace(s, ",")
+	}
+	for _, item := range items {
+		if !contains(fi.enum, item) {
+			return fmt.Errorf("%w %q: accepted values are %s", ErrInvalidEnum, item, strings.Join(fi.enum, ", "))
+		}
+	}
+	return nil
+}
+
+// CheckRequired checks that all the Required flags of the flag set were passed
+// in the command line or set by their environment variable.
+// It must be called after fs.Parse. The returned error lists the missing flags.
+func CheckRequired(fs *flag.FlagSet) error {
+	missing := []string{}
+	for _, d := range flagDefs(fs) {
+		if d.info == nil || !d.info.required {
+			continue
+		}
+		if IsPassed(fs, strings.Join(d.names, ",")) {
+			continue
+		}
+		if env := d.env(); env != "" {
+			if _, ok := os.LookupEnv(env); ok {
+				continue
+			}
+		}
+		missing = append(missing, flagArg(d.names[0]))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	token := strings.Join(missing, ", ")
+	return &Error{
+		Path:  fs.Name(),
+		Token: token,
+		Kind:  ErrRequiredFlag,
+		msg:   fmt.Sprintf("%s: %s: %s", fs.Name(), ErrRequiredFlag, token),
+	}
+}
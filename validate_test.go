@@ -0,0 +1,122 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestCheckRequired(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		env        string
+		wantErrMsg string
+	}{
+		{
+			name: "all passed",
+			args: "--config cfg.yaml -d db",
+		},
+		{
+			name: "alias passed",
+			args: "-c cfg.yaml --db db",
+		},
+		{
+			name: "env set",
+			args: "-c cfg.yaml",
+			env:  "db",
+		},
+		{
+			name:       "one missing",
+			args:       "-d db",
+			wantErrMsg: "test: required flag not passed: --config",
+		},
+		{
+			name:       "all missing",
+			args:       "-w 2",
+			wantErrMsg: "test: required flag not passed: --config, --database",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TEST_DATABASE", tt.env)
+			}
+
+			var (
+				config   string
+				database string
+				workers  int
+			)
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			AliasedStringVar(fs, &config, "config,c", "", "config file", Required())
+			AliasedStringVar(fs, &database, "database,db,d", "", "database", Required(), Env("TEST_DATABASE"))
+			AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+
+			if err := fs.Parse(splitTrimSpace(tt.args, " ")); err != nil {
+				t.Fatalf("Parse() error = %v, want nil", err)
+			}
+
+			err := CheckRequired(fs)
+			if tt.wantErrMsg == "" {
+				if err != nil {
+					t.Errorf("CheckRequired() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrRequiredFlag) || err.Error() != tt.wantErrMsg {
+				t.Errorf("CheckRequired() error = %v, want %q", err, tt.wantErrMsg)
+			}
+		})
+	}
+}
+
+func Test_Enum(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantErrMsg string
+	}{
+		{
+			name: "valid values",
+			args: "-m U -t yaml,json",
+		},
+		{
+			name:       "invalid value",
+			args:       "-m X",
+			wantErrMsg: `invalid value "X": accepted values are 1, U, A`,
+		},
+		{
+			name:       "invalid item",
+			args:       "-t yaml,xml",
+			wantErrMsg: `invalid value "xml": accepted values are yaml, toml, json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mode  string
+				types []string
+			)
+			var out strings.Builder
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&out)
+			AliasedStringVar(fs, &mode, "mode,m", "1", "result mode", Enum("1", "U", "A"))
+			AliasedStringsVar(fs, &types, "types,t", "config types", Enum("yaml", "toml", "json"))
+
+			err := fs.Parse(splitTrimSpace(tt.args, " "))
+			if tt.wantErrMsg == "" {
+				if err != nil {
+					t.Errorf("Parse() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErrMsg)
+			}
+		})
+	}
+}
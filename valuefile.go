@@ -0,0 +1,91 @@
+package flagx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrValueTooLarge is returned if the value read by a FromFile flag
+// exceeds MaxValueFileSize bytes.
+var ErrValueTooLarge = errors.New("value too large")
+
+// MaxValueFileSize is the maximum size, in bytes, of a value
+// read from a file or the standard input by a FromFile flag.
+var MaxValueFileSize int64 = 1 << 20
+
+// FromFile option lets the flag read its value from a file or the standard input:
+// the "@path" value reads the value from the file at path, and the "-" value
+// reads it from Stdin. The "@@" prefix passes a value beginning with a literal '@'.
+//
+// The value of a strings flag (see AliasedStringsVar) gets an item for each
+// line of the file; empty lines and lines beginning with '#' are ignored.
+// The value of the other flags is the content of the file,
+// without the trailing newline.
+//
+// Note that, if the response files of the command are enabled,
+// the value must be passed as "--name=@path".
+func FromFile() FlagOption {
+	return func(fi *flagInfo) {
+		fi.fromFile = true
+	}
+}
+
+// readValue returns the content of the source of the value:
+// "-" for Stdin, or "@path" for the file at path.
+// The ok result is false if value is not a source of value.
+func readValue(value string) (content string, ok bool, err error) {
+	var r io.Reader
+
+	switch {
+	case value == "-":
+		r = Stdin
+	case len(value) > 1 && value[0] == '@' && value[1] != '@':
+		f, err := os.Open(value[1:])
+		if err != nil {
+			return "", true, err
+		}
+		defer f.Close()
+		r = f
+	default:
+		return "", false, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxValueFileSize+1))
+	if err != nil {
+		return "", true, err
+	}
+	if int64(len(data)) > MaxValueFileSize {
+		return "", true, wrapErrorf(ErrValueTooLarge, "%s: %s (max %d bytes)", value, ErrValueTooLarge, MaxValueFileSize)
+	}
+	return string(data), true, nil
+}
+
+// setFromFile sets the value v reading it from the source specified by value.
+// It returns false if value does not specify a source.
+func setFromFile(v *optValue, value string) (bool, error) {
+	content, ok, err := readValue(value)
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("flag %q: %w", v.name, err)
+	}
+
+	if _, isList := v.value.(*astring); !isList {
+		return true, v.set(strings.TrimRight(content, "\r\n"))
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := v.set(line); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,138 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_FromFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"isins.txt": "# isins\nisin1\n\n  isin2  \nisin3,isin4\n",
+		"query.sql": "select *\nfrom quotes\n",
+		"workers":   "8\n",
+		"big.txt":   strings.Repeat("x", 100),
+	})
+	path := func(name string) string { return "@" + filepath.Join(dir, name) }
+
+	defer func(max int64) { MaxValueFileSize = max }(MaxValueFileSize)
+	MaxValueFileSize = 64
+
+	defer func() { Stdin = os.Stdin }()
+
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		wantIsins   []string
+		wantQuery   string
+		wantWorkers int
+		wantErr     error
+		wantErrMsg  string
+	}{
+		{
+			name:        "no files",
+			args:        []string{"-i", "isin1", "--query", "select"},
+			wantIsins:   []string{"isin1"},
+			wantQuery:   "select",
+			wantWorkers: 1,
+		},
+		{
+			name:        "strings from file",
+			args:        []string{"-i", "isin0", "--isins", path("isins.txt")},
+			wantIsins:   []string{"isin0", "isin1", "isin2", "isin3", "isin4"},
+			wantWorkers: 1,
+		},
+		{
+			name:        "string from file",
+			args:        []string{"--query", path("query.sql")},
+			wantQuery:   "select *\nfrom quotes",
+			wantWorkers: 1,
+		},
+		{
+			name:        "int from file",
+			args:        []string{"-w", path("workers")},
+			wantWorkers: 8,
+		},
+		{
+			name:        "string from stdin",
+			args:        []string{"--query", "-"},
+			stdin:       "select 1\n",
+			wantQuery:   "select 1",
+			wantWorkers: 1,
+		},
+		{
+			name:        "escaped at",
+			args:        []string{"--query", "@@user"},
+			wantQuery:   "@user",
+			wantWorkers: 1,
+		},
+		{
+			name:       "file not found",
+			args:       []string{"--query", path("not-found")},
+			wantErr:    fs.ErrNotExist,
+			wantErrMsg: `flag "query": open `,
+		},
+		{
+			name:       "file too large",
+			args:       []string{"--isins", path("big.txt")},
+			wantErr:    ErrValueTooLarge,
+			wantErrMsg: `flag "isins": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				isins   []string
+				query   string
+				workers int
+			)
+			Stdin = strings.NewReader(tt.stdin)
+
+			var out strings.Builder
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&out)
+			AliasedStringsVar(fs, &isins, "isins,i", "list of isins", FromFile())
+			AliasedStringVar(fs, &query, "query,q", "", "query", FromFile())
+			AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers", FromFile())
+
+			err := fs.Parse(tt.args)
+			if tt.wantErr != nil {
+				// the flag package does not wrap the error of the value
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error: got %v, want %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: got %q, want nil", err)
+			}
+
+			if !reflect.DeepEqual(isins, tt.wantIsins) {
+				t.Errorf("isins: got %q, want %q", isins, tt.wantIsins)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query: got %q, want %q", query, tt.wantQuery)
+			}
+			if workers != tt.wantWorkers {
+				t.Errorf("workers: got %v, want %v", workers, tt.wantWorkers)
+			}
+		})
+	}
+}
+
+func Test_readValue(t *testing.T) {
+	_, ok, err := readValue("@" + filepath.Join(t.TempDir(), "not-found"))
+	if !ok || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("readValue(): got %v, %v, want true, %v", ok, err, fs.ErrNotExist)
+	}
+	if _, ok, _ := readValue("value"); ok {
+		t.Errorf("readValue(%q): got ok, want not ok", "value")
+	}
+}
@@ -0,0 +1,87 @@
+package flagx
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultHelpWidth is the width of the help if it can't be detected.
+const DefaultHelpWidth = 80
+
+// minWrapWidth is the minimum width available for the wrapped text:
+// a narrower text is wrapped at this width anyway.
+const minWrapWidth = 20
+
+// HelpWidth is the width, in characters, of the help generated by flagx.
+// If zero, the width is taken from the COLUMNS environment variable or,
+// if the output is a terminal, from the size of the terminal;
+// otherwise DefaultHelpWidth is used.
+// Set it to a fixed value for test purposes.
+var HelpWidth int
+
+// helpWidth returns the width of the help written to w.
+func helpWidth(w io.Writer) int {
+	if HelpWidth > 0 {
+		return HelpWidth
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := w.(*os.File); ok {
+		if n, ok := terminalWidth(f); ok && n > 0 {
+			return n
+		}
+	}
+	return DefaultHelpWidth
+}
+
+// wrapText wraps the text s in lines of at most width characters,
+// supposing the first line starts at column indent: the following lines
+// are indented by indent spaces (hanging indent).
+// The explicit newlines are kept, and the lines obtained by wrapping
+// an explicit line keep its leading spaces. The blank lines are left empty.
+// A word longer than the available width is not split.
+func wrapText(s string, indent, width int) string {
+	avail := width - indent
+	if avail < minWrapWidth {
+		avail = minWrapWidth
+	}
+	pad := strings.Repeat(" ", indent)
+
+	lines := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		prefix := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		cur := prefix
+		for _, word := range strings.Fields(line) {
+			if cur != prefix && utf8.RuneCountInString(cur)+1+utf8.RuneCountInString(word) > avail {
+				lines = append(lines, cur)
+				cur = prefix
+			}
+			if cur != prefix {
+				cur += " "
+			}
+			cur += word
+		}
+		if cur == prefix {
+			// a blank line
+			cur = ""
+		}
+		lines = append(lines, cur)
+	}
+
+	var b strings.Builder
+	for j, line := range lines {
+		if j > 0 {
+			b.WriteString("\n")
+			if line != "" {
+				// don't pad the blank lines
+				b.WriteString(pad)
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package flagx
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_wrapText(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		indent int
+		width  int
+		want   string
+	}{
+		{
+			name:  "short",
+			s:     "short text",
+			width: 80,
+			want:  "short text",
+		},
+		{
+			name:  "wrapped",
+			s:     "the quick brown fox jumps over the lazy dog",
+			width: 20,
+			want:  "the quick brown fox\njumps over the lazy\ndog",
+		},
+		{
+			name:   "hanging indent",
+			s:      "the quick brown fox jumps over the lazy dog",
+			indent: 10,
+			width:  30,
+			want:   "the quick brown fox\n          jumps over the lazy\n          dog",
+		},
+		{
+			name:   "minimum width",
+			s:      "the quick brown fox jumps over the lazy dog",
+			indent: 70,
+			width:  80,
+			want:   "the quick brown fox\n" + strings.Repeat(" ", 70) + "jumps over the lazy\n" + strings.Repeat(" ", 70) + "dog",
+		},
+		{
+			name:  "explicit newlines",
+			s:     "first line\n  second line is longer\n\nlast",
+			width: 20,
+			want:  "first line\n  second line is\n  longer\n\nlast",
+		},
+		{
+			name:   "blank lines",
+			s:      "a\n\n  \nb",
+			indent: 10,
+			width:  80,
+			want:   "a\n\n\n          b",
+		},
+		{
+			name:  "long word",
+			s:     "a verylongwordthatdoesnotfit b",
+			width: 20,
+			want:  "a\nverylongwordthatdoesnotfit\nb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(tt.s, tt.indent, tt.width); got != tt.want {
+				t.Errorf("wrapText():\ngot\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_helpWidth(t *testing.T) {
+	defer func(w int) { HelpWidth = w }(HelpWidth)
+
+	var buf strings.Builder
+
+	HelpWidth = 0
+	t.Setenv("COLUMNS", "")
+	if got := helpWidth(&buf); got != DefaultHelpWidth {
+		t.Errorf("helpWidth(): got %d, want %d", got, DefaultHelpWidth)
+	}
+
+	t.Setenv("COLUMNS", "100")
+	if got := helpWidth(&buf); got != 100 {
+		t.Errorf("helpWidth() with COLUMNS: got %d, want %d", got, 100)
+	}
+
+	HelpWidth = 60
+	if got := helpWidth(&buf); got != 60 {
+		t.Errorf("helpWidth() with HelpWidth: got %d, want %d", got, 60)
+	}
+}
+
+func Test_PrintDefaults_Wrap(t *testing.T) {
+	defer func(w int) { HelpWidth = w }(HelpWidth)
+	HelpWidth = 60
+
+	var (
+		configType string
+		mode       string
+		workers    int
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	AliasedStringVar(fs, &configType, "config-type", "yaml", "used if config file does not have the extension in the name; accepted values are: YAML, TOML and JSON")
+	AliasedStringVar(fs, &mode, "mode,m", "1", "result mode:\n"+
+		`"1" first success or last error (default)`+"\n"+
+		`"U" all errors until first success`+"\n"+
+		`"A" all`)
+	AliasedIntVar(fs, &workers, "workers,w", 1, "number of workers")
+
+	var buf strings.Builder
+	fs.SetOutput(&buf)
+	PrintDefaults(fs)
+
+	want := `        --config-type string  used if config file does not
+                              have the extension in the
+                              name; accepted values are:
+                              YAML, TOML and JSON (default
+                              "yaml")
+    -m, --mode        string  result mode:
+                              "1" first success or last
+                              error (default)
+                              "U" all errors until first
+                              success
+                              "A" all (default "1")
+    -w, --workers     int     number of workers (default 1)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("PrintDefaults():\ngot\n%s\nwant\n%s", got, want)
+	}
+}